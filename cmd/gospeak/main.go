@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -28,6 +29,15 @@ func main() {
 
 	targets, err := gospeak.Parse(schemaDir)
 	if err != nil {
+		var diagnostics gospeak.Diagnostics
+		if errors.As(err, &diagnostics) {
+			// Print errors like the Go compiler, so editors can jump to them.
+			for _, diagnostic := range diagnostics {
+				fmt.Fprintln(os.Stderr, diagnostic)
+			}
+			fmt.Fprintf(os.Stderr, "failed to parse Go schema: %v errors\n", len(diagnostics))
+			os.Exit(1)
+		}
 		fmt.Fprintf(os.Stderr, "failed to parse Go schema: %v\n", err)
 		os.Exit(1)
 	}
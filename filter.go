@@ -0,0 +1,188 @@
+package gospeak
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// MethodFilter selects service methods exposed to a single target, ie.:
+//
+//	//go:webrpc typescript -client -include=Get*,List* -exclude-annotated=internal -out=./client.gen.ts
+type MethodFilter struct {
+	Include          []string // Method name patterns (path.Match syntax) to keep. Keeps all methods if empty.
+	Exclude          []string // Method name patterns (path.Match syntax) to drop.
+	IncludeAnnotated []string // Keep only methods with at least one of these annotations. Keeps all methods if empty.
+	ExcludeAnnotated []string // Drop methods with any of these annotations.
+}
+
+func (f *MethodFilter) IsEmpty() bool {
+	return f == nil || len(f.Include)+len(f.Exclude)+len(f.IncludeAnnotated)+len(f.ExcludeAnnotated) == 0
+}
+
+func (f *MethodFilter) validate() error {
+	for _, pattern := range append(f.Include, f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid method pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether the given method passes the filter.
+func (f *MethodFilter) Match(method *schema.Method) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	if len(f.Include) > 0 && !matchAny(f.Include, method.Name) {
+		return false
+	}
+	if matchAny(f.Exclude, method.Name) {
+		return false
+	}
+	if len(f.IncludeAnnotated) > 0 && !hasAnyAnnotation(method, f.IncludeAnnotated) {
+		return false
+	}
+	if hasAnyAnnotation(method, f.ExcludeAnnotated) {
+		return false
+	}
+
+	return true
+}
+
+// Apply returns a copy of the schema with services pruned to methods matching the filter,
+// followed by pruning of types no longer reachable from any method. The original schema
+// is left untouched, so it can be shared across multiple targets.
+func (f *MethodFilter) Apply(s *schema.WebRPCSchema) *schema.WebRPCSchema {
+	if f.IsEmpty() {
+		return s
+	}
+
+	filtered := &schema.WebRPCSchema{
+		WebrpcVersion: s.WebrpcVersion,
+		SchemaName:    s.SchemaName,
+		SchemaVersion: s.SchemaVersion,
+		Errors:        s.Errors,
+	}
+
+	for _, service := range s.Services {
+		filteredService := &schema.Service{
+			Name:     service.Name,
+			Comments: service.Comments,
+			Schema:   filtered, // denormalize/back-reference
+		}
+
+		for _, method := range service.Methods {
+			if !f.Match(method) {
+				continue
+			}
+			filteredMethod := *method
+			filteredMethod.Service = filteredService // denormalize/back-reference
+			filteredService.Methods = append(filteredService.Methods, &filteredMethod)
+		}
+
+		if len(filteredService.Methods) == 0 {
+			continue
+		}
+		filtered.Services = append(filtered.Services, filteredService)
+	}
+
+	filtered.Types = reachableTypes(s.Types, filtered.Services)
+
+	return filtered
+}
+
+// Returns types referenced by the given services, directly or transitively, in their original order.
+func reachableTypes(allTypes []*schema.Type, services []*schema.Service) []*schema.Type {
+	typesByName := map[string]*schema.Type{}
+	for _, typ := range allTypes {
+		typesByName[typ.Name] = typ
+	}
+
+	reachable := map[*schema.Type]bool{}
+
+	var visit func(varType *schema.VarType)
+	visit = func(varType *schema.VarType) {
+		if varType == nil {
+			return
+		}
+
+		// Enum fields only reference the enum type by name.
+		typ := typesByName[varType.Expr]
+		if varType.Struct != nil && varType.Struct.Type != nil {
+			typ = varType.Struct.Type
+		}
+		if typ != nil && !reachable[typ] {
+			reachable[typ] = true
+			for _, field := range typ.Fields {
+				visit(field.Type)
+			}
+
+			// Union variants are only referenced by name.
+			for _, meta := range typ.TypeExtra.Meta {
+				if oneOf, ok := meta["oneOf"].([]string); ok {
+					for _, variant := range oneOf {
+						visit(&schema.VarType{Expr: variant})
+					}
+				}
+			}
+		}
+
+		if varType.List != nil {
+			visit(varType.List.Elem)
+		}
+		if varType.Map != nil {
+			visit(varType.Map.Key)
+			visit(varType.Map.Value)
+		}
+	}
+
+	for _, service := range services {
+		for _, method := range service.Methods {
+			for _, arg := range method.Inputs {
+				visit(arg.Type)
+			}
+			for _, arg := range method.Outputs {
+				visit(arg.Type)
+			}
+		}
+	}
+
+	// Exported constants are kept, since they're not referenced by methods.
+	for _, typ := range allTypes {
+		for _, meta := range typ.TypeExtra.Meta {
+			if constants, _ := meta["constants"].(bool); constants {
+				visit(&schema.VarType{Expr: typ.Name})
+			}
+		}
+	}
+
+	var types []*schema.Type
+	for _, typ := range allTypes {
+		if reachable[typ] {
+			types = append(types, typ)
+		}
+	}
+
+	return types
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyAnnotation(method *schema.Method, names []string) bool {
+	for _, name := range names {
+		if _, ok := method.Annotations[name]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Argument binding to HTTP header or query param, given by //gospeak:arg directive.
+type argBinding struct {
+	arg   string // method argument name
+	kind  string // "header" or "query"
+	name  string // header or query param name
+	pos   token.Pos
+	bound bool // whether the argument was found
+}
+
+var (
+	headerNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$") // RFC 9110 token
+	queryNameRegex  = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)            // RFC 3986 unreserved
+)
+
+// Headers set by the transport, which can't be bound to arguments.
+var reservedHeaders = []string{"Content-Type", "Content-Length", "Transfer-Encoding", "Connection"}
+
+// Collects argument bindings from `//gospeak:arg <argument> header=<name>|query=<name>` directives, ie.:
+//
+//	//gospeak:arg tenantID header=X-Tenant-ID
+//	//gospeak:arg ifMatch header=If-Match
+//	//gospeak:arg etag header=ETag
+//	UpdatePet(ctx context.Context, tenantID string, ifMatch string, pet *Pet) (etag string, err error)
+//
+// Input arguments can be bound to request headers or query params, output arguments
+// to response headers. The bindings are added to the argument meta, ie. {"header": "X-Tenant-ID"},
+// so generated servers can extract them and clients can send them.
+func (p *Parser) getArgumentBindings(method *types.Func) (map[string]*argBinding, error) {
+	bindings := map[string]*argBinding{}
+	names := map[string]string{} // "header:x-tenant-id" => argument
+
+	for _, directive := range ParseDirectives(p.methodDoc(method)) {
+		if directive.Name != "arg" {
+			continue
+		}
+
+		binding, err := parseArgBinding(directive)
+		if err != nil {
+			diagnostic := p.errorf(directive.Pos, "%v(): invalid //gospeak:arg %v: %v", method.Name(), directive.Value, err)
+			diagnostic.Fix = "use //gospeak:arg <argument> header=<Header-Name> or //gospeak:arg <argument> query=<param>"
+			return nil, diagnostic
+		}
+
+		if _, ok := bindings[binding.arg]; ok {
+			return nil, p.errorf(directive.Pos, "%v(): duplicate //gospeak:arg binding of argument %v", method.Name(), binding.arg)
+		}
+		key := binding.kind + ":" + strings.ToLower(binding.name)
+		if arg, ok := names[key]; ok {
+			return nil, p.errorf(directive.Pos, "%v(): %v %v is already bound to argument %v", method.Name(), binding.kind, binding.name, arg)
+		}
+
+		bindings[binding.arg] = binding
+		names[key] = binding.arg
+	}
+
+	return bindings, nil
+}
+
+// Parses "tenantID header=X-Tenant-ID" directive value.
+func parseArgBinding(directive Directive) (*argBinding, error) {
+	parts := strings.Fields(directive.Value)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected <argument> header=<name> or <argument> query=<name>")
+	}
+
+	kind, name, _ := strings.Cut(parts[1], "=")
+	switch kind {
+	case "header":
+		if !headerNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		for _, reserved := range reservedHeaders {
+			if strings.EqualFold(name, reserved) {
+				return nil, fmt.Errorf("header %v is reserved", http.CanonicalHeaderKey(name))
+			}
+		}
+	case "query":
+		if !queryNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid query param name %q", name)
+		}
+	default:
+		return nil, fmt.Errorf("unknown binding %q: expected header=<name> or query=<name>", parts[1])
+	}
+
+	return &argBinding{
+		arg:  parts[0],
+		kind: kind,
+		name: name,
+		pos:  directive.Pos,
+	}, nil
+}
+
+// Binds the argument to header or query param.
+func (p *Parser) bindArgument(methodName string, arg *schema.MethodArgument, binding *argBinding) error {
+	binding.bound = true
+
+	if arg.OutputArg && binding.kind != "header" {
+		return p.errorf(binding.pos, "%v(): output argument %v can be bound to response header only", methodName, arg.Name)
+	}
+
+	argType := arg.Type
+	if binding.kind == "query" && argType.Type == schema.T_List {
+		argType = argType.List.Elem // repeated query param, ie. ?tag=a&tag=b
+	}
+	if !isScalarType(argType) {
+		return p.errorf(binding.pos, "%v(): argument %v of type %v can't be bound to %v %v", methodName, arg.Name, arg.Type.Expr, binding.kind, binding.name)
+	}
+
+	arg.TypeExtra.Meta = append(arg.TypeExtra.Meta, schema.TypeFieldMeta{binding.kind: binding.name})
+	return nil
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// ConstantsTypeName is the name of the schema type holding constants exported by //gospeak:export directive.
+const ConstantsTypeName = "Constants"
+
+// CollectConstants collects package-level constants marked by //gospeak:export directive, ie.:
+//
+//	//gospeak:export
+//	const MaxPageSize = 100
+//
+//	//gospeak:export
+//	const (
+//		DefaultLocale        = "en"
+//		DefaultStatus Status = 1 // enum value, ie. "pending"
+//	)
+//
+// The constants are always validated, but they're added to the schema only if the
+// target opts in by -constants flag (see ExportConstants), since generators with no
+// constants support would emit the type as a regular struct. The constants are added
+// as fields of the "Constants" struct type with {"const": <value>} meta. The type has
+// {"constants": true} meta, so generators can emit the fields as constants of the
+// clients. Constants of enum types reference the enum by their type and by the enum
+// value name.
+//
+// Unexported constants of an exported block are skipped. Exporting a single unexported
+// constant is an error.
+func (p *Parser) CollectConstants() error {
+	constantsType := &schema.Type{
+		Kind: schema.TypeKind_Struct,
+		Name: ConstantsTypeName,
+		TypeExtra: schema.TypeExtra{
+			Meta: []schema.TypeFieldMeta{{"constants": true}},
+		},
+	}
+
+	var diagnostics Diagnostics
+	var decl types.Object
+
+	for _, file := range p.Pkg.Syntax {
+		for _, fileDecl := range file.Decls {
+			constDecl, ok := fileDecl.(*ast.GenDecl)
+			if !ok || constDecl.Tok != token.CONST {
+				continue
+			}
+
+			// The directive exports either all constants of the block, or a single constant.
+			_, exportBlock := findDirective(ParseDirectives(constDecl.Doc), "export")
+
+			for _, spec := range constDecl.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+
+				directives := append(ParseDirectives(valueSpec.Doc), ParseDirectives(valueSpec.Comment)...)
+				_, exportSpec := findDirective(directives, "export")
+				if !exportSpec && !exportBlock {
+					continue
+				}
+
+				for _, name := range valueSpec.Names {
+					obj, ok := p.Pkg.TypesInfo.Defs[name].(*types.Const)
+					if !ok || name.Name == "_" {
+						continue
+					}
+					if !obj.Exported() && exportBlock && constDecl.Lparen.IsValid() && !exportSpec {
+						continue // unexported constants of export blocks are kept private
+					}
+
+					field, err := p.parseConstant(obj)
+					if err != nil {
+						diagnostics = append(diagnostics, p.diagnose(name.Pos(), fmt.Errorf("exporting constant %v: %w", name.Name, err))...)
+						continue
+					}
+
+					if decl == nil {
+						decl = obj
+					}
+					constantsType.Fields = append(constantsType.Fields, field)
+				}
+			}
+		}
+	}
+
+	if len(diagnostics) > 0 {
+		return diagnostics
+	}
+
+	if decl == nil || !p.ExportConstants {
+		return nil
+	}
+
+	if existing, ok := p.TypeNameOwners[ConstantsTypeName]; ok {
+		return p.errorf(decl.Pos(), "type name %v of exported constants collides with %v", ConstantsTypeName, existing)
+	}
+	p.TypeNameOwners[ConstantsTypeName] = "//gospeak:export constants"
+
+	p.addType(constantsType, decl)
+
+	return nil
+}
+
+// Parses Go constant into a field of the "Constants" schema type.
+func (p *Parser) parseConstant(obj *types.Const) (*schema.TypeField, error) {
+	if !obj.Exported() {
+		return nil, fmt.Errorf("constant is not exported")
+	}
+
+	typ := types.Default(obj.Type()) // untyped constants, ie. untyped int => int
+
+	varType, err := p.ParseNamedType(p.GoTypeName(typ), typ)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := p.constantValue(typ, varType, obj.Val())
+	if err != nil {
+		return nil, err
+	}
+
+	field := &schema.TypeField{
+		Name: obj.Name(),
+		Type: varType,
+		TypeExtra: schema.TypeExtra{
+			Meta: []schema.TypeFieldMeta{
+				{"const": value},
+			},
+		},
+	}
+	if format := typeFormat(typ); format != "" {
+		field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{"format": format})
+	}
+
+	return field, nil
+}
+
+// Returns JSON value of the Go constant, ie. "pending" for enum constant of value 1.
+func (p *Parser) constantValue(typ types.Type, varType *schema.VarType, val constant.Value) (any, error) {
+	if enum, ok := p.ParsedEnumTypes[typ.String()]; ok {
+		for _, enumValue := range enum.Fields {
+			if enumValue.TypeExtra.Value == val.ExactString() {
+				return enumValue.Name, nil
+			}
+		}
+		return nil, fmt.Errorf("value %v is not defined by enum %v", val.ExactString(), enum.Name)
+	}
+
+	switch varType.Type {
+	case schema.T_String:
+		if val.Kind() == constant.String {
+			return constant.StringVal(val), nil
+		}
+
+	case schema.T_Bool:
+		if val.Kind() == constant.Bool {
+			return constant.BoolVal(val), nil
+		}
+
+	case schema.T_Int, schema.T_Int8, schema.T_Int16, schema.T_Int32, schema.T_Int64:
+		if n, exact := constant.Int64Val(val); exact {
+			return n, nil
+		}
+
+	case schema.T_Uint, schema.T_Uint8, schema.T_Uint16, schema.T_Uint32, schema.T_Uint64:
+		if n, exact := constant.Uint64Val(val); exact {
+			return n, nil
+		}
+
+	case schema.T_Float32, schema.T_Float64:
+		f, _ := constant.Float64Val(constant.ToFloat(val))
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("constant of Go type %v (webrpc type %v) is not supported", typ, varType.Expr)
+}
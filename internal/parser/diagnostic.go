@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diagnostic is a parser error pointing at the offending method, field or type.
+type Diagnostic struct {
+	Pos token.Position
+	Msg string
+	Fix string // Suggested fix, if any.
+}
+
+var _ error = Diagnostic{}
+
+// Error formats the diagnostic like the Go compiler, ie. file.go:12:2: msg,
+// so editors can jump to the source position.
+func (d Diagnostic) Error() string {
+	msg := d.Msg
+	if d.Fix != "" {
+		msg = fmt.Sprintf("%v (fix: %v)", msg, d.Fix)
+	}
+	if !d.Pos.IsValid() {
+		return msg
+	}
+	return fmt.Sprintf("%v: %v", d.Pos, msg)
+}
+
+// Diagnostics is a list of all errors found while parsing, sorted by position.
+type Diagnostics []Diagnostic
+
+var _ error = Diagnostics{}
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+	for i, diagnostic := range d {
+		lines[i] = diagnostic.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Sort sorts diagnostics by file, line and column.
+func (d Diagnostics) Sort() {
+	sort.SliceStable(d, func(i, j int) bool {
+		a, b := d[i].Pos, d[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// Returns diagnostics carried by the given error. If the error doesn't carry
+// any, returns a single diagnostic positioned at pos.
+func (p *Parser) diagnose(pos token.Pos, err error) Diagnostics {
+	var diagnostics Diagnostics
+	if errors.As(err, &diagnostics) {
+		return diagnostics
+	}
+
+	var diagnostic Diagnostic
+	if errors.As(err, &diagnostic) {
+		return Diagnostics{diagnostic}
+	}
+
+	return Diagnostics{{
+		Pos: p.Pkg.Fset.Position(pos),
+		Msg: err.Error(),
+	}}
+}
+
+// Returns a new diagnostic positioned at pos.
+func (p *Parser) errorf(pos token.Pos, format string, a ...any) Diagnostic {
+	return Diagnostic{
+		Pos: p.Pkg.Fset.Position(pos),
+		Msg: fmt.Sprintf(format, a...),
+	}
+}
+
+// ParsePosition parses position in the "file:line:column" format used by golang.org/x/tools/go/packages errors.
+func ParsePosition(pos string) token.Position {
+	var position token.Position
+
+	rest, col, ok := cutLastNumber(pos)
+	if !ok {
+		position.Filename = pos
+		return position
+	}
+
+	file, line, ok := cutLastNumber(rest)
+	if !ok {
+		// file:line
+		position.Filename = rest
+		position.Line = col
+		return position
+	}
+
+	position.Filename = file
+	position.Line = line
+	position.Column = col
+	return position
+}
+
+func cutLastNumber(s string) (string, int, bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0, false
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], n, true
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestParsePosition(t *testing.T) {
+	tt := []struct {
+		in  string
+		out token.Position
+	}{
+		{in: ``},
+		{in: `-`, out: token.Position{Filename: "-"}},
+		{in: `/path/to/file.go`, out: token.Position{Filename: "/path/to/file.go"}},
+		{in: `/path/to/file.go:12`, out: token.Position{Filename: "/path/to/file.go", Line: 12}},
+		{in: `/path/to/file.go:12:5`, out: token.Position{Filename: "/path/to/file.go", Line: 12, Column: 5}},
+		{in: `C:\path\file.go:12:5`, out: token.Position{Filename: `C:\path\file.go`, Line: 12, Column: 5}},
+	}
+	for _, tc := range tt {
+		if got := ParsePosition(tc.in); got != tc.out {
+			t.Errorf("ParsePosition(%q): expected %+v, got %+v", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestDiagnosticError(t *testing.T) {
+	diagnostics := Diagnostics{
+		{Pos: token.Position{Filename: "b.go", Line: 1, Column: 2}, Msg: "second"},
+		{Pos: token.Position{Filename: "a.go", Line: 10, Column: 2}, Msg: "first"},
+		{Msg: "no position"},
+	}
+	diagnostics.Sort()
+
+	want := "no position\na.go:10:2: first\nb.go:1:2: second"
+	if got := diagnostics.Error(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+const directivePrefix = "//gospeak:"
+
+// Directive is a single `//gospeak:<name> <value>` comment line, ie.:
+//
+//	//gospeak:deprecated use GetPetV2
+//	//gospeak:auth role=admin
+//	//gospeak:internal
+type Directive struct {
+	Name  string // deprecated
+	Value string // use GetPetV2
+	Pos   token.Pos
+}
+
+// ParseDirectives returns all `//gospeak:` directives found in the given comment group.
+func ParseDirectives(doc *ast.CommentGroup) []Directive {
+	if doc == nil {
+		return nil
+	}
+
+	var directives []Directive
+	for _, comment := range doc.List {
+		text, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+
+		name, value, _ := strings.Cut(strings.TrimSpace(text), " ")
+		if name == "" {
+			continue
+		}
+
+		directives = append(directives, Directive{
+			Name:  name,
+			Value: strings.TrimSpace(value),
+			Pos:   comment.Pos(),
+		})
+	}
+
+	return directives
+}
+
+// methodDoc finds the doc comment of the given interface method in the package syntax.
+// Works for methods of embedded interfaces too, as long as they're defined in the same package.
+func (p *Parser) methodDoc(method *types.Func) *ast.CommentGroup {
+	if field := p.astField(method.Pos()); field != nil {
+		return field.Doc
+	}
+	return nil
+}
+
+// fieldDirectives returns directives of the given struct field, found both
+// in its doc comment and in its line comment. Returns nil for fields declared
+// outside of the schema package.
+func (p *Parser) fieldDirectives(field *types.Var) []Directive {
+	astField := p.astField(field.Pos())
+	if astField == nil {
+		return nil
+	}
+	return append(ParseDirectives(astField.Doc), ParseDirectives(astField.Comment)...)
+}
+
+// astField finds the interface method or struct field declared at the given position.
+// The fields are indexed by position on the first call, so the package syntax is walked once.
+func (p *Parser) astField(pos token.Pos) *ast.Field {
+	if p.astFields == nil {
+		p.astFields = map[token.Pos]*ast.Field{}
+		for _, file := range p.Pkg.Syntax {
+			ast.Inspect(file, func(n ast.Node) bool {
+				if field, ok := n.(*ast.Field); ok {
+					for _, name := range field.Names {
+						p.astFields[name.Pos()] = field
+					}
+				}
+				return true
+			})
+		}
+	}
+	return p.astFields[pos]
+}
+
+// typeDoc finds the doc comment of the given type declaration in the package syntax.
+// Returns nil for types declared outside of the schema package. The type declarations
+// are indexed by position on the first call, so the package syntax is walked once.
+func (p *Parser) typeDoc(obj *types.TypeName) *ast.CommentGroup {
+	if p.typeDocs == nil {
+		p.typeDocs = map[token.Pos]*ast.CommentGroup{}
+		for _, file := range p.Pkg.Syntax {
+			for _, decl := range file.Decls {
+				typeDeclaration, ok := decl.(*ast.GenDecl)
+				if !ok || typeDeclaration.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range typeDeclaration.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					// Grouped declarations have doc on the spec, ie. type ( ... ).
+					doc := typeSpec.Doc
+					if doc == nil {
+						doc = typeDeclaration.Doc
+					}
+					p.typeDocs[typeSpec.Name.Pos()] = doc
+				}
+			}
+		}
+	}
+	return p.typeDocs[obj.Pos()]
+}
+
+// Returns the value of the given directive, ie. "kind" for //gospeak:discriminator kind.
+func findDirective(directives []Directive, name string) (string, bool) {
+	for _, directive := range directives {
+		if directive.Name == name {
+			return directive.Value, true
+		}
+	}
+	return "", false
+}
@@ -66,7 +66,22 @@ func (p *Parser) CollectEnums() error {
 
 						enumElemType, ok := schema.CoreTypeFromString[strings.ToLower(enumTypeName)]
 						if !ok {
-							return fmt.Errorf("unknown enum type %v", enumTypeName)
+							return p.errorf(selExpr.Sel.Pos(), "unknown enum type %v", enumTypeName)
+						}
+
+						goType := fmt.Sprintf("%v.%v", p.Pkg.PkgPath, enumName)
+
+						doc := typeDeclaration.Doc
+
+						// The enum name can be set explicitly by //gospeak:name directive, see typeName().
+						if value, ok := findDirective(ParseDirectives(doc), "name"); ok {
+							if !isIdentifier(value) {
+								return p.errorf(typeSpec.Name.Pos(), "invalid //gospeak:name %q: expected identifier", value)
+							}
+							enumName = value
+						}
+						if owner, ok := p.TypeNameOwners[enumName]; ok && owner != goType {
+							return p.errorf(typeSpec.Name.Pos(), "type name %v of %v collides with %v", enumName, goType, owner)
 						}
 
 						enumType := &schema.Type{
@@ -79,7 +94,6 @@ func (p *Parser) CollectEnums() error {
 							Fields: []*schema.TypeField{}, // webrpc TODO: should be Enums
 						}
 
-						doc := typeDeclaration.Doc
 						if doc != nil {
 							// name       value
 							// ----------------
@@ -87,7 +101,11 @@ func (p *Parser) CollectEnums() error {
 							// pending  = 1
 							// closed   = 2
 							// new      = 3
-							for i, comment := range doc.List {
+							i := 0
+							for _, comment := range doc.List {
+								if strings.HasPrefix(comment.Text, directivePrefix) {
+									continue
+								}
 								commentValue, _ := strings.CutPrefix(comment.Text, "//")
 								name, value, found := strings.Cut(commentValue, "=") // approved = 0
 								if !found {                                          // approved
@@ -100,11 +118,13 @@ func (p *Parser) CollectEnums() error {
 										Value: strings.TrimSpace(value),
 									},
 								})
+								i++
 							}
 						}
 
-						p.Schema.Types = append(p.Schema.Types, enumType)
-						p.ParsedEnumTypes[fmt.Sprintf("%v.%v", p.Pkg.PkgPath, enumName)] = enumType
+						p.addType(enumType, p.Pkg.TypesInfo.Defs[typeSpec.Name])
+						p.ParsedEnumTypes[goType] = enumType
+						p.TypeNameOwners[enumName] = goType
 					}
 				}
 			}
@@ -0,0 +1,290 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Adds default and example values of the struct field to the field meta, ie.:
+//
+//	Limit int      `json:"limit" default:"10"`                => {"default": 10}
+//	Email string   `json:"email" example:"john@example.com"`  => {"example": "john@example.com"}
+//	Tags  []string `json:"tags" example:"[\"go\", \"rpc\"]"` => {"example": ["go", "rpc"]}
+//
+// The values can be given by field comment directives too, which is handy for JSON:
+//
+//	//gospeak:example {"name": "Fido", "age": 3}
+//	Pet *Pet `json:"pet"`
+//
+// Scalar values are given as is, lists, maps and structs as JSON. The values are
+// type checked against the field type, so generators can emit them into OpenAPI
+// default/example and mock servers can return them.
+func (p *Parser) applyExamples(structField *schema.TypeField, field *types.Var, tag reflect.StructTag) error {
+	directives := p.fieldDirectives(field)
+
+	for _, key := range []string{"default", "example"} {
+		value, ok := tag.Lookup(key)
+		if directive, found := findDirective(directives, key); found {
+			if ok {
+				return fmt.Errorf("%v value given by both struct tag and //gospeak:%v directive", key, key)
+			}
+			value, ok = directive, true
+		}
+		if !ok {
+			continue
+		}
+
+		v, err := p.parseExample(structField, value)
+		if err != nil {
+			return fmt.Errorf("invalid %v value %q: %w", key, value, err)
+		}
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{key: v})
+	}
+
+	return nil
+}
+
+// Parses default or example value of the given field type.
+func (p *Parser) parseExample(structField *schema.TypeField, value string) (any, error) {
+	if value == "null" && (structField.TypeExtra.Optional || hasMeta(structField.TypeExtra.Meta, "nullable")) {
+		return nil, nil
+	}
+
+	switch structField.Type.Type {
+	case schema.T_List, schema.T_Map, schema.T_Struct, schema.T_Any:
+		dec := json.NewDecoder(bytes.NewReader([]byte(value)))
+		dec.UseNumber()
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("expected JSON value of %v: %w", structField.Type.Expr, err)
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("expected single JSON value of %v", structField.Type.Expr)
+		}
+		if err := p.checkExample(structField.Type, v, formatMeta(structField)); err != nil {
+			return nil, err
+		}
+		return v, nil
+
+	default:
+		// Scalars are given as is, ie. `default:"10"` or `example:"john@example.com"`.
+		return p.parseScalarExample(structField.Type, value, formatMeta(structField))
+	}
+}
+
+// Checks decoded JSON value against the given type.
+func (p *Parser) checkExample(varType *schema.VarType, v any, format string) error {
+	if varType == nil {
+		return nil
+	}
+
+	switch varType.Type {
+	case schema.T_Any:
+		return nil
+
+	case schema.T_Null:
+		if v != nil {
+			return fmt.Errorf("expected null, got %v", jsonKind(v))
+		}
+		return nil
+
+	case schema.T_List:
+		list, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("expected JSON array of %v, got %v", varType.Expr, jsonKind(v))
+		}
+		for i, item := range list {
+			if err := p.checkExample(varType.List.Elem, item, ""); err != nil {
+				return fmt.Errorf("[%v]: %w", i, err)
+			}
+		}
+		return nil
+
+	case schema.T_Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected JSON object of %v, got %v", varType.Expr, jsonKind(v))
+		}
+		for _, key := range sortedKeys(m) {
+			if _, err := p.parseScalarExample(varType.Map.Key, key, ""); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			if err := p.checkExample(varType.Map.Value, m[key], ""); err != nil {
+				return fmt.Errorf("[%q]: %w", key, err)
+			}
+		}
+		return nil
+
+	case schema.T_Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected JSON object of %v, got %v", varType.Expr, jsonKind(v))
+		}
+		if varType.Struct == nil || varType.Struct.Type == nil {
+			return nil // struct is still being parsed, ie. recursive type
+		}
+		for _, key := range sortedKeys(m) {
+			field := findJSONField(varType.Struct.Type.Fields, key)
+			if field == nil {
+				return fmt.Errorf("unknown field %q of %v", key, varType.Expr)
+			}
+			if m[key] == nil && (field.TypeExtra.Optional || hasMeta(field.TypeExtra.Meta, "nullable")) {
+				continue
+			}
+			if err := p.checkExample(field.Type, m[key], formatMeta(field)); err != nil {
+				return fmt.Errorf(".%v: %w", key, err)
+			}
+		}
+		return nil
+
+	case schema.T_Bool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("expected %v, got %v", varType.Expr, jsonKind(v))
+		}
+		return nil
+	}
+
+	// Numbers and strings.
+	switch value := v.(type) {
+	case json.Number:
+		if varType.Type == schema.T_String || varType.Type == schema.T_Timestamp {
+			return fmt.Errorf("expected %v, got number", varType.Expr)
+		}
+		_, err := p.parseScalarExample(varType, value.String(), format)
+		return err
+	case string:
+		if varType.Type != schema.T_String && varType.Type != schema.T_Timestamp {
+			return fmt.Errorf("expected %v, got string", varType.Expr)
+		}
+		_, err := p.parseScalarExample(varType, value, format)
+		return err
+	}
+
+	return fmt.Errorf("expected %v, got %v", varType.Expr, jsonKind(v))
+}
+
+// Parses scalar value of the given type, ie. "10" of int64 into int64(10).
+func (p *Parser) parseScalarExample(varType *schema.VarType, value string, format string) (any, error) {
+	switch varType.Type {
+	case schema.T_String:
+		if enum := p.Schema.GetTypeByName(varType.Expr); enum != nil && enum.Kind == schema.TypeKind_Enum {
+			if !slices.ContainsFunc(enum.Fields, func(f *schema.TypeField) bool { return f.Name == value }) {
+				return nil, fmt.Errorf("expected %v enum value", enum.Name)
+			}
+			return value, nil
+		}
+		if err := checkFormat(format, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+
+	case schema.T_Timestamp:
+		if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+			return nil, fmt.Errorf("expected RFC 3339 timestamp, ie. 2006-01-02T15:04:05Z")
+		}
+		return value, nil
+
+	case schema.T_Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected bool")
+		}
+		return b, nil
+
+	case schema.T_Int, schema.T_Int8, schema.T_Int16, schema.T_Int32, schema.T_Int64:
+		n, err := strconv.ParseInt(value, 10, bitSize(varType.Type))
+		if err != nil {
+			return nil, fmt.Errorf("expected %v", varType.Expr)
+		}
+		return n, nil
+
+	case schema.T_Uint, schema.T_Uint8, schema.T_Uint16, schema.T_Uint32, schema.T_Uint64:
+		n, err := strconv.ParseUint(value, 10, bitSize(varType.Type))
+		if err != nil {
+			return nil, fmt.Errorf("expected %v", varType.Expr)
+		}
+		return n, nil
+
+	case schema.T_Float32, schema.T_Float64:
+		f, err := strconv.ParseFloat(value, bitSize(varType.Type))
+		if err != nil {
+			return nil, fmt.Errorf("expected %v", varType.Expr)
+		}
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("%v values are not supported", varType.Expr)
+}
+
+// Checks string value of the given "format" meta, see TypeFormats.
+func checkFormat(format string, value string) error {
+	var err error
+	switch format {
+	case "byte":
+		_, err = base64.StdEncoding.DecodeString(value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "time":
+		_, err = time.Parse(time.TimeOnly, value)
+	}
+	if err != nil {
+		return fmt.Errorf("expected %v format", format)
+	}
+	return nil
+}
+
+func bitSize(t schema.CoreType) int {
+	switch t {
+	case schema.T_Int8, schema.T_Uint8:
+		return 8
+	case schema.T_Int16, schema.T_Uint16:
+		return 16
+	case schema.T_Int32, schema.T_Uint32, schema.T_Float32:
+		return 32
+	}
+	return 64
+}
+
+func formatMeta(field *schema.TypeField) string {
+	for _, m := range field.TypeExtra.Meta {
+		if format, ok := m["format"].(string); ok {
+			return format
+		}
+	}
+	return ""
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+	return "object"
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
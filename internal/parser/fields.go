@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Struct field resolved by encoding/json field promotion rules.
+type jsonField struct {
+	name        string // JSON field name
+	tagged      bool   // the name was given by json struct tag
+	index       []int  // field index sequence, len(index) is the embedding depth
+	field       *types.Var
+	jsonTag     JsonTag
+	gospeakTag  GospeakTag
+	validateTag string // `validate:"..."` struct tag value
+	structTag   reflect.StructTag
+	optional    bool // promoted from embedded pointer, which is omitted by encoding/json if nil
+	hidden      bool // hidden from the API by `gospeak:"-"` struct tag of the field or the embedded struct
+}
+
+// Embedded struct to be scanned for promoted fields.
+type embeddedStruct struct {
+	typ       types.Type
+	structTyp *types.Struct
+	index     []int
+	optional  bool
+	hidden    bool
+}
+
+// Collects JSON fields of the given struct, exactly how encoding/json does it:
+//   - fields of embedded structs (including unexported structs and pointers) are promoted,
+//   - a field at shallower depth dominates deeper fields of the same name,
+//   - a tagged field dominates untagged fields at the same depth,
+//   - otherwise fields of the same name are ambiguous and dropped,
+//   - embedded non-struct types are treated as fields named after the type.
+//
+// The `json:",inline"` option promotes fields of a non-embedded struct field too.
+func (p *Parser) collectStructFields(structTyp *types.Struct) ([]jsonField, Diagnostics) {
+	var diagnostics Diagnostics
+	var fields []jsonField
+
+	current := []embeddedStruct{}
+	next := []embeddedStruct{{typ: structTyp, structTyp: structTyp}}
+
+	// Count of embedded types at the current and the next depth.
+	count, nextCount := map[types.Type]int{}, map[types.Type]int{}
+
+	visited := map[types.Type]bool{}
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[types.Type]int{}
+
+		for _, embedded := range current {
+			if visited[embedded.typ] {
+				continue
+			}
+			visited[embedded.typ] = true
+
+			for i := 0; i < embedded.structTyp.NumFields(); i++ {
+				structField := embedded.structTyp.Field(i)
+
+				fieldType := types.Unalias(structField.Type())
+				ptr, isPtr := fieldType.(*types.Pointer)
+				if isPtr {
+					fieldType = types.Unalias(ptr.Elem())
+				}
+				embeddedStructTyp, isStruct := fieldType.Underlying().(*types.Struct)
+
+				if structField.Embedded() {
+					if !structField.Exported() && !isStruct {
+						// Ignore embedded fields of unexported non-struct types.
+						continue
+					}
+					// Do not ignore embedded fields of unexported struct types, since they may have exported fields.
+				} else if !structField.Exported() {
+					// Ignore unexported non-embedded fields.
+					continue
+				}
+
+				structTags := embedded.structTyp.Tag(i)
+
+				jsonTag, _ := GetJsonTag(structTags)
+				if jsonTag.Name == "-" { // struct field ignored by `json:"-"` struct tag
+					continue
+				}
+
+				gospeakTag, _, err := GetGospeakTag(structTags)
+				if err != nil {
+					diagnostics = append(diagnostics, p.diagnose(structField.Pos(), fmt.Errorf("parsing struct field %v: %w", structField.Name(), err))...)
+					continue
+				}
+
+				index := append(slices.Clone(embedded.index), i)
+
+				// Promote fields of embedded struct, unless it's given a name by json tag.
+				promote := structField.Embedded() && jsonTag.Name == ""
+				if jsonTag.Inline {
+					promote = true
+				}
+				if promote && isStruct {
+					nextCount[fieldType]++
+					if nextCount[fieldType] == 1 {
+						next = append(next, embeddedStruct{
+							typ:       fieldType,
+							structTyp: embeddedStructTyp,
+							index:     index,
+							optional:  embedded.optional || isPtr,
+							hidden:    embedded.hidden || gospeakTag.Ignore,
+						})
+					}
+					continue
+				}
+
+				validateTag, _ := reflect.StructTag(structTags).Lookup("validate")
+
+				field := jsonField{
+					name:        jsonTag.Name,
+					tagged:      jsonTag.Name != "",
+					index:       index,
+					field:       structField,
+					jsonTag:     jsonTag,
+					gospeakTag:  gospeakTag,
+					validateTag: validateTag,
+					structTag:   reflect.StructTag(structTags),
+					optional:    embedded.optional,
+					hidden:      embedded.hidden || gospeakTag.Ignore,
+				}
+				if field.name == "" {
+					field.name = structField.Name()
+				}
+				fields = append(fields, field)
+
+				if count[embedded.typ] > 1 {
+					// If there were multiple instances of the same embedded struct at the same depth,
+					// add the field twice, so it's annihilated as ambiguous by dominantFields().
+					fields = append(fields, field)
+				}
+			}
+		}
+	}
+
+	fields = dominantFields(fields)
+
+	// Struct fields hidden from the API by `gospeak:"-"` struct tag, including fields
+	// promoted from hidden embedded structs, still take part in the field dominance,
+	// since they're encoded by encoding/json.
+	fields = slices.DeleteFunc(fields, func(f jsonField) bool {
+		return f.hidden
+	})
+
+	return fields, diagnostics
+}
+
+// Returns the dominant fields, ordered by the field index sequence, and drops ambiguous fields.
+func dominantFields(fields []jsonField) []jsonField {
+	// Sort by name, breaking ties with depth, then tagged first, then index sequence.
+	slices.SortStableFunc(fields, func(a, b jsonField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := len(a.index) - len(b.index); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		// Find the sequence of fields with the same name.
+		name := fields[i].name
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != name {
+				break
+			}
+		}
+
+		// The first field dominates, unless there's another field of the same depth and tagging.
+		if advance > 1 && len(fields[i].index) == len(fields[i+1].index) && fields[i].tagged == fields[i+1].tagged {
+			continue // ambiguous field
+		}
+		out = append(out, fields[i])
+	}
+
+	slices.SortFunc(out, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	return out
+}
@@ -9,33 +9,26 @@ import (
 )
 
 func (p *Parser) GoTypeName(typ types.Type) string {
-	name := typ.String() // []*github.com/golang-cz/gospeak/pkg.Typ
-
-	if typNamed, ok := typ.(*types.Named); ok {
-		// Versioned packages.
-		// github.com/gofrs/uuid/v5.UUID => // github.com/gofrs/uuid.UUID
-		if typNamed.Obj().Pkg() != nil {
-			if !strings.Contains(name, typNamed.Obj().Pkg().Name()+".") {
-				name = strings.ReplaceAll(name, typNamed.Obj().Type().String(), typNamed.Obj().Pkg().Name()+"."+typNamed.Obj().Name())
-			}
-		}
-	}
-
-	name = strings.ReplaceAll(name, "*", "") // []github.com/golang-cz/gospeak/pkg.Typ
-
-	firstLetter := findFirstLetter(name)
-	prefix := name[:firstLetter] // []
-	name = name[firstLetter:]    // github.com/golang-cz/gospeak/pkg.Typ
+	// Qualify types by package name, ie. []*github.com/golang-cz/gospeak/pkg.Typ => []*pkg.Typ.
+	// Versioned packages are qualified by their name too, ie. github.com/gofrs/uuid/v5.UUID => uuid.UUID.
+	name := types.TypeString(typ, p.qualifier)
 
-	name = strings.TrimPrefix(name, p.SchemaPkgName+".")       // Typ (ignore root Pkg)
-	name = strings.TrimPrefix(name, "command-line-arguments.") // Typ (ignore "command-line-arguments" Pkg autogenerated by Go tool chain)
-	name = filepath.Base(name)                                 // pkg.Typ
+	name = strings.ReplaceAll(name, "*", "") // []pkg.Typ
 
 	if name == "invalid type" {
 		name = "invalidType"
 	}
 
-	return prefix + name // []*pkg.Typ
+	return name
+}
+
+// Returns package name qualifier for Go type names. Types of the schema package are not qualified.
+func (p *Parser) qualifier(pkg *types.Package) string {
+	switch pkg.Path() {
+	case p.SchemaPkgName, "command-line-arguments": // "command-line-arguments" Pkg autogenerated by Go tool chain
+		return ""
+	}
+	return pkg.Name()
 }
 
 func (p *Parser) GoTypeImport(typ types.Type) string {
@@ -44,6 +37,11 @@ func (p *Parser) GoTypeImport(typ types.Type) string {
 	firstLetter := findFirstLetter(name)
 	name = name[firstLetter:] // github.com/golang-cz/gospeak/pkg.Typ
 
+	// Import the generic type package, ie. github.com/golang-cz/gospeak.Optional[int64] => github.com/golang-cz/gospeak.Optional
+	if i := strings.IndexByte(name, '['); i > 0 {
+		name = name[:i]
+	}
+
 	lastDot := strings.LastIndex(name, ".")
 	if lastDot <= 0 {
 		return ""
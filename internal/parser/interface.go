@@ -3,7 +3,6 @@ package parser
 import (
 	"fmt"
 	"go/types"
-	"strings"
 
 	"github.com/webrpc/webrpc/schema"
 )
@@ -14,56 +13,35 @@ func (p *Parser) ParseInterfaceMethods(iface *types.Interface, name string) erro
 		Schema: p.Schema, // denormalize/back-reference
 	}
 
-	// Loop over the interface's methods.
+	// Loop over the interface's methods. Collect errors from all methods, so we can report them at once.
+	var diagnostics Diagnostics
 	for i := 0; i < iface.NumMethods(); i++ {
 		method := iface.Method(i)
 		if !method.Exported() {
 			continue
 		}
 
-		methodName := method.Id()
-
-		methodSignature, ok := method.Type().(*types.Signature)
-		if !ok {
-			return fmt.Errorf("%v(): failed to get method signature", methodName)
-		}
-
-		methodParams := methodSignature.Params()
-		inputs, err := p.getMethodArguments(methodParams, true)
+		serviceMethod, err := p.parseMethod(method)
 		if err != nil {
-			return fmt.Errorf("%v(): failed to get inputs: %w", methodName, err)
-		}
-
-		// First method argument must be of type context.Context.
-		if methodParams.Len() == 0 {
-			return fmt.Errorf("%v(): first method argument must be context.Context: no arguments defined", methodName)
-		}
-		if err := ensureContextType(methodParams.At(0).Type()); err != nil {
-			return fmt.Errorf("%v(): first method argument must be context.Context: %w", methodName, err)
+			diagnostics = append(diagnostics, p.diagnose(method.Pos(), err)...)
+			continue
 		}
-		inputs = inputs[1:] // Cut it off. The gen/golang adds context.Context as first method argument automatically.
 
-		methodResults := methodSignature.Results()
-		outputs, err := p.getMethodArguments(methodResults, false)
-		if err != nil {
-			return fmt.Errorf("%v(): failed to get outputs: %w", methodName, err)
+		if route, ok := serviceMethod.Annotations["route"]; ok {
+			services := append([]*schema.Service{service}, p.Schema.Services...)
+			if conflictService, conflictMethod := findRoute(services, route.Value); conflictMethod != nil {
+				diagnostics = append(diagnostics, p.errorf(method.Pos(), "%v(): route %v conflicts with %v.%v()", method.Name(), route.Value, conflictService.Name, conflictMethod.Name))
+				continue
+			}
 		}
 
-		// Last method return value must be of type error.
-		if methodResults.Len() == 0 {
-			return fmt.Errorf("%v(): last return value must be context.Context: no return values defined", methodName)
-		}
-		if err := ensureErrorType(methodResults.At(methodResults.Len() - 1).Type()); err != nil {
-			return fmt.Errorf("%v(): first method argument must be context.Context: %w", methodName, err)
-		}
-		outputs = outputs[:len(outputs)-1] // Cut it off. The gen/golang adds error as a last return value automatically.
+		serviceMethod.Service = service // denormalize/back-reference
+		service.Methods = append(service.Methods, serviceMethod)
+	}
 
-		service.Methods = append(service.Methods, &schema.Method{
-			Name:    methodName,
-			Inputs:  inputs,
-			Outputs: outputs,
-			Service: service, // denormalize/back-reference
-		})
+	if len(diagnostics) > 0 {
+		diagnostics.Sort()
+		return diagnostics
 	}
 
 	if len(service.Methods) == 0 {
@@ -75,46 +53,134 @@ func (p *Parser) ParseInterfaceMethods(iface *types.Interface, name string) erro
 	return nil
 }
 
-func (p *Parser) getMethodArguments(params *types.Tuple, isInput bool) ([]*schema.MethodArgument, error) {
+func (p *Parser) parseMethod(method *types.Func) (*schema.Method, error) {
+	methodName := method.Id()
+
+	methodSignature, ok := method.Type().(*types.Signature)
+	if !ok {
+		return nil, fmt.Errorf("%v(): failed to get method signature", methodName)
+	}
+
+	if err := p.validateMethodSignature(method, methodSignature); err != nil {
+		return nil, err
+	}
+
+	bindings, err := p.getArgumentBindings(method)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := p.getMethodArguments(methodName, methodSignature.Params(), true, bindings)
+	if err != nil {
+		return nil, fmt.Errorf("%v(): failed to get inputs: %w", methodName, err)
+	}
+	inputs = inputs[1:] // Cut off context.Context. The gen/golang adds it as first method argument automatically.
+
+	results, streamOutput, err := p.streamResults(methodName, methodSignature.Results())
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := p.getMethodArguments(methodName, results, false, bindings)
+	if err != nil {
+		return nil, fmt.Errorf("%v(): failed to get outputs: %w", methodName, err)
+	}
+	outputs = outputs[:len(outputs)-1] // Cut off error. The gen/golang adds it as a last return value automatically.
+
+	for _, binding := range bindings {
+		if !binding.bound {
+			return nil, p.errorf(binding.pos, "%v(): //gospeak:arg %v doesn't match any method argument", methodName, binding.arg)
+		}
+	}
+
+	annotations, err := p.getMethodAnnotations(method)
+	if err != nil {
+		return nil, fmt.Errorf("%v(): %w", methodName, err)
+	}
+
+	pagination, err := paginationAnnotation(methodName, methodSignature.Params(), inputs, results, outputs)
+	if err != nil {
+		return nil, err
+	}
+	if pagination != nil {
+		if _, ok := annotations[pagination.AnnotationType]; ok {
+			return nil, fmt.Errorf("%v(): //gospeak:paginated annotation is added automatically to methods returning gospeak.Page", methodName)
+		}
+		annotations[pagination.AnnotationType] = pagination
+	}
+
+	if route, ok := annotations["route"]; ok {
+		value, err := parseRoute(route.Value, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("%v(): invalid //gospeak:route %v: %w", methodName, route.Value, err)
+		}
+		route.Value = value
+	}
+
+	return &schema.Method{
+		Name:         methodName,
+		Annotations:  annotations,
+		StreamOutput: streamOutput,
+		Inputs:       inputs,
+		Outputs:      outputs,
+	}, nil
+}
+
+// Collects method annotations from `//gospeak:<name> <value>` directives, ie.:
+//
+//	//gospeak:deprecated use GetPetV2
+//	//gospeak:auth role=admin
+//	//gospeak:internal
+//	GetPet(ctx context.Context, ID int64) (*Pet, error)
+//
+// Unknown annotations are passed through verbatim, so custom templates can use them.
+func (p *Parser) getMethodAnnotations(method *types.Func) (schema.Annotations, error) {
+	annotations := schema.Annotations{}
+
+	for _, directive := range ParseDirectives(p.methodDoc(method)) {
+		if directive.Name == "arg" {
+			continue // argument bindings, see getArgumentBindings()
+		}
+		if _, ok := annotations[directive.Name]; ok {
+			return nil, fmt.Errorf("duplicate //gospeak:%v annotation", directive.Name)
+		}
+		annotations[directive.Name] = &schema.Annotation{
+			AnnotationType: directive.Name,
+			Value:          directive.Value,
+		}
+	}
+
+	return annotations, nil
+}
+
+func (p *Parser) getMethodArguments(methodName string, params *types.Tuple, isInput bool, bindings map[string]*argBinding) ([]*schema.MethodArgument, error) {
 	var args []*schema.MethodArgument
 
 	for i := 0; i < params.Len(); i++ {
 		param := params.At(i)
 		typ := param.Type()
 
-		name := param.Name()
-		if name == "" {
-			// If the argument's name is not defined, come up with a name based on its type.
-			// *pkg.User => user
-			// []*pkg.User => userList
-			// []string => stringList
-
-			name = typ.String()
-			name = name[findFirstLetter(name):]
-			if i := strings.LastIndex(name, "."); i > 0 {
-				name = name[i+1:]
-			}
-			name = firstToLower(name)
-
-			switch typ.(type) {
-			case *types.Slice, *types.Array:
-				name += "List"
-			}
+		name := argumentName(param, isInput)
 
-			if isInput {
-				name += "Req"
-			}
+		// Type name will be resolved deeper down the stack. Anonymous structs
+		// are named after the method and the argument, ie. CreatePetRequest.
+		goTypeName := ""
+		if hasAnonymousStruct(typ) {
+			goTypeName = methodName + firstToUpper(name)
 		}
 
-		varType, err := p.ParseType(typ) // Type name will be resolved deeper down the stack.
+		varType, err := p.ParseNamedType(goTypeName, typ)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse argument %v %v: %w", name, typ, err)
+			return nil, p.diagnose(param.Pos(), fmt.Errorf("failed to parse argument %v %v: %w", name, typ, err))
 		}
 
 		optional := false
 		if varType.Struct != nil && varType.Struct.Type != nil {
 			optional = true // TODO: SHould we use varType.Struct.Type.Optional instead?
 		}
+		if _, isOptional, _, ok := unwrapOptional(typ); ok && isOptional {
+			optional = true
+		}
 
 		arg := &schema.MethodArgument{
 			Name:      name,
@@ -124,48 +190,16 @@ func (p *Parser) getMethodArguments(params *types.Tuple, isInput bool) ([]*schem
 			Optional:  optional,
 		}
 
+		// Skip context.Context input and error output, which are cut off by parseMethod().
+		contextOrError := (isInput && i == 0) || (!isInput && i == params.Len()-1)
+		if binding, ok := bindings[name]; ok && !contextOrError {
+			if err := p.bindArgument(methodName, arg, binding); err != nil {
+				return nil, err
+			}
+		}
+
 		args = append(args, arg)
 	}
 
 	return args, nil
 }
-
-func ensureContextType(typ types.Type) (err error) {
-	namedType, ok := typ.(*types.Named)
-	if !ok {
-		return fmt.Errorf("expected named type: found type %T (%+v)", typ, typ)
-	}
-
-	if _, ok := namedType.Underlying().(*types.Interface); !ok {
-		return fmt.Errorf("expected underlying interface: found type %T (%+v)", typ, typ)
-	}
-
-	pkgName := namedType.Obj().Pkg().Name()
-	typeName := namedType.Obj().Name()
-
-	if pkgName != "context" && typeName != "Context" {
-		return fmt.Errorf("expected context.Context: found %v.%v", pkgName, typeName)
-	}
-
-	return nil
-}
-
-func ensureErrorType(typ types.Type) (err error) {
-	namedType, ok := typ.(*types.Named)
-	if !ok {
-		return fmt.Errorf("expected named type: found type %T (%+v)", typ, typ)
-	}
-
-	if _, ok := namedType.Underlying().(*types.Interface); !ok {
-		return fmt.Errorf("expected underlying interface: found type %T (%+v)", typ, typ)
-	}
-
-	pkgName := namedType.Obj().Pkg()
-	typeName := namedType.Obj().Name()
-
-	if pkgName != nil && typeName != "error" {
-		return fmt.Errorf("expected error: found %v.%v", pkgName, typeName)
-	}
-
-	return nil
-}
@@ -8,7 +8,7 @@ import (
 )
 
 func (p *Parser) ParseMap(typeName string, m *types.Map) (*schema.VarType, error) {
-	key, err := p.ParseNamedType(typeName, m.Key())
+	key, err := p.parseMapKey(typeName, m.Key())
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse map key type: %w", err)
 	}
@@ -29,3 +29,53 @@ func (p *Parser) ParseMap(typeName string, m *types.Map) (*schema.VarType, error
 
 	return varType, nil
 }
+
+// Parses map key type. JSON object keys are always strings, so we allow the same
+// key types as encoding/json does:
+//   - gospeak enums, ie. map<Status,T>
+//   - types implementing encoding.TextMarshaler, ie. map<string,T> for uuid.UUID keys
+//   - strings, ie. map<string,T>
+//   - integers, ie. map<int64,T>, which are stringified in JSON
+func (p *Parser) parseMapKey(typeName string, key types.Type) (*schema.VarType, error) {
+	key = types.Unalias(key)
+
+	if p.mapKeyEnum(key) != "" {
+		return p.ParseNamedType(typeName, key)
+	}
+
+	if named, ok := key.(*types.Named); ok && isTextMarshaler(named, named.Obj().Pkg()) {
+		return &schema.VarType{
+			Expr: "string",
+			Type: schema.T_String,
+		}, nil
+	}
+
+	if basic, ok := key.Underlying().(*types.Basic); ok {
+		switch {
+		case basic.Info()&types.IsString != 0:
+			return &schema.VarType{
+				Expr: "string",
+				Type: schema.T_String,
+			}, nil
+		case basic.Kind() == types.Uintptr:
+			// No uintptr in webrpc, encoding/json encodes it as unsigned integer.
+			return p.ParseBasic(types.Typ[types.Uint64])
+		case basic.Info()&types.IsInteger != 0:
+			return p.ParseBasic(basic)
+		}
+	}
+
+	return nil, fmt.Errorf("invalid map key type %v: JSON object keys must be strings, integers, enums or encoding.TextMarshaler types", p.GoTypeName(key))
+}
+
+// Returns enum name of the given map key type, if it's a gospeak enum.
+func (p *Parser) mapKeyEnum(key types.Type) string {
+	named, ok := types.Unalias(key).(*types.Named)
+	if !ok {
+		return ""
+	}
+	if enum, ok := p.ParsedEnumTypes[named.String()]; ok {
+		return enum.Name
+	}
+	return ""
+}
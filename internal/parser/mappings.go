@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// DefaultTypeMappings maps well-known Go types to webrpc types.
+var DefaultTypeMappings = map[string]string{
+	"time.Time":                              "timestamp",
+	"encoding/json.RawMessage":               "any", // arbitrary JSON value
+	"encoding/json/jsontext.Value":           "any", // json.RawMessage is an alias of jsontext.Value with GOEXPERIMENT=jsonv2
+	"github.com/golang-cz/gospeak.Duration":  "string",
+	"github.com/golang-cz/gospeak.Date":      "string",
+	"github.com/golang-cz/gospeak.TimeOfDay": "string",
+}
+
+// TypeFormats maps well-known Go types to "format" field meta, so generators
+// can emit OpenAPI formats and language-specific types.
+var TypeFormats = map[string]string{
+	"time.Duration":                          "duration-ns", // int64 nanoseconds
+	"github.com/golang-cz/gospeak.Duration":  "duration",    // ISO-8601 duration, ie. PT1H30M
+	"github.com/golang-cz/gospeak.Date":      "date",        // 2006-01-02
+	"github.com/golang-cz/gospeak.TimeOfDay": "time",        // 15:04:05
+}
+
+// CollectTypeMappings collects Go type to webrpc type mappings from package directives, ie.:
+//
+//	//gospeak:map github.com/shopspring/decimal.Decimal=string
+//	//gospeak:map net/netip.Addr=string
+//	package proto
+//
+// The directives can be placed in any comment of the package files.
+func (p *Parser) CollectTypeMappings() error {
+	for _, file := range p.Pkg.Syntax {
+		for _, commentGroup := range file.Comments {
+			for _, directive := range ParseDirectives(commentGroup) {
+				if directive.Name != "map" {
+					continue
+				}
+
+				goType, webrpcType, err := parseTypeMapping(directive.Value)
+				if err != nil {
+					diagnostic := p.errorf(directive.Pos, "invalid //gospeak:map %v: %v", directive.Value, err)
+					diagnostic.Fix = "use //gospeak:map <import/path>.<Type>=<webrpc type>"
+					return diagnostic
+				}
+
+				p.TypeMappings[goType] = webrpcType
+			}
+		}
+	}
+
+	return nil
+}
+
+// Parses "github.com/shopspring/decimal.Decimal=string" type mapping.
+func parseTypeMapping(mapping string) (goType string, webrpcType string, err error) {
+	goType, webrpcType, ok := strings.Cut(mapping, "=")
+	goType, webrpcType = strings.TrimSpace(goType), strings.TrimSpace(webrpcType)
+	if !ok || goType == "" || webrpcType == "" {
+		return "", "", fmt.Errorf("expected <Go type>=<webrpc type>")
+	}
+
+	if lastDot := strings.LastIndex(goType, "."); lastDot <= 0 || lastDot == len(goType)-1 {
+		return "", "", fmt.Errorf("expected fully qualified Go type, ie. github.com/shopspring/decimal.Decimal")
+	}
+
+	return goType, webrpcType, nil
+}
+
+// Returns mapped webrpc type for the given named Go type, if any.
+func (p *Parser) mappedType(named *types.Named) (*schema.VarType, bool, error) {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil, false, nil
+	}
+
+	goType := obj.Pkg().Path() + "." + obj.Name()
+	webrpcType, ok := p.TypeMappings[goType]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var varType schema.VarType
+	if err := schema.ParseVarTypeExpr(p.Schema, webrpcType, &varType); err != nil {
+		return nil, false, fmt.Errorf("failed to map %v to webrpc type %q: %w", goType, webrpcType, err)
+	}
+
+	return &varType, true, nil
+}
+
+// Returns "format" field meta value for the given Go type, if any.
+func typeFormat(typ types.Type) string {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	if isByteSlice(typ) {
+		return "byte" // base64 encoded string
+	}
+
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+
+	return TypeFormats[named.Obj().Pkg().Path()+"."+named.Obj().Name()]
+}
+
+// Returns true if the given type is encoded as base64 string by encoding/json, ie. []byte
+// or named byte slice, unless it implements json.Marshaler or encoding.TextMarshaler.
+func isByteSlice(typ types.Type) bool {
+	typ = types.Unalias(typ)
+	if isMarshaler(typ) {
+		return false
+	}
+
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+
+	elem := types.Unalias(slice.Elem())
+	if basic, ok := elem.Underlying().(*types.Basic); !ok || basic.Kind() != types.Uint8 {
+		return false
+	}
+
+	// Elements implementing marshalers are encoded individually as JSON array.
+	return !isMarshaler(elem)
+}
+
+// Returns true if the given named type implements json.Marshaler or encoding.TextMarshaler.
+func isMarshaler(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+	pkg := named.Obj().Pkg()
+	return isJsonMarshaller(named, pkg) || isTextMarshaler(named, pkg)
+}
@@ -8,6 +8,9 @@ import (
 )
 
 func (p *Parser) ParseNamedType(goTypeName string, typ types.Type) (varType *schema.VarType, err error) {
+	// Resolve type aliases (ie. `type Ctx = context.Context`) to the actual type.
+	typ = types.Unalias(typ)
+
 	// On cache HIT, return a pointer to parsedType from cache.
 	if parsedType, ok := p.ParsedTypes[typ]; ok {
 		return parsedType, nil
@@ -28,6 +31,18 @@ func (p *Parser) ParseNamedType(goTypeName string, typ types.Type) (varType *sch
 			*cacheDoNotReturn = *varType // Update the cache value via pointer dereference.
 			varType = cacheDoNotReturn
 		}
+
+		// Add the parsed type to unions that referenced it while it was being parsed (recursive types).
+		pending := p.pendingVariants[typ]
+		delete(p.pendingVariants, typ)
+		for _, addVariant := range pending {
+			if err != nil {
+				break
+			}
+			if err = addVariant(varType); err != nil {
+				varType = nil
+			}
+		}
 	}()
 
 	switch v := typ.(type) {
@@ -36,13 +51,16 @@ func (p *Parser) ParseNamedType(goTypeName string, typ types.Type) (varType *sch
 		underlying := v.Underlying()
 		goTypeName := p.GoTypeName(typ)
 
-		if pkg != nil {
-			if goTypeName == "time.Time" {
-				return &schema.VarType{
-					Expr: "timestamp",
-					Type: schema.T_Timestamp,
-				}, nil
-			}
+		// Types mapped by DefaultTypeMappings or //gospeak:map directives, ie. time.Time => timestamp.
+		if mapped, ok, err := p.mappedType(v); err != nil {
+			return nil, err
+		} else if ok {
+			return mapped, nil
+		}
+
+		// Optional and nullable wrappers, ie. []gospeak.Nullable[int64] => []int64.
+		if elem, _, _, ok := unwrapOptional(v); ok {
+			return p.ParseNamedType(p.GoTypeName(elem), elem)
 		}
 
 		if enum, ok := p.ParsedEnumTypes[typ.String()]; ok {
@@ -62,8 +80,24 @@ func (p *Parser) ParseNamedType(goTypeName string, typ types.Type) (varType *sch
 			}, nil
 		}
 
+		// Named byte slice is encoded as base64 string, ie. `type Blob []byte`.
+		if isByteSlice(v) {
+			return &schema.VarType{
+				Expr: "string",
+				Type: schema.T_String,
+			}, nil
+		}
+
 		switch u := underlying.(type) {
 
+		case *types.Interface:
+			// Sealed interface with //gospeak:discriminator directive.
+			if discriminator, ok := p.unionDiscriminator(v, u); ok {
+				return p.ParseUnion(v, u, discriminator)
+			}
+
+			return p.ParseNamedType(goTypeName, underlying)
+
 		case *types.Pointer:
 			// Named pointer. Webrpc can't handle that.
 			// Example:
@@ -128,6 +162,36 @@ func (p *Parser) ParseNamedType(goTypeName string, typ types.Type) (varType *sch
 				}, nil
 			}
 
+			if structTyp, ok := u.(*types.Struct); ok {
+				name, err := p.typeName(v)
+				if err != nil {
+					return nil, err
+				}
+				// Generic type instances identical in JSON share the type, see typeName().
+				if existing := p.Schema.GetTypeByName(name); existing != nil && v.TypeArgs().Len() > 0 {
+					return &schema.VarType{
+						Expr: name,
+						Type: schema.T_Struct,
+						Struct: &schema.VarStructType{
+							Name: name,
+							Type: existing,
+						},
+					}, nil
+				}
+
+				structType, err := p.parseStruct(name, goTypeName, structTyp, v.Obj())
+				if err != nil {
+					return nil, err
+				}
+				if isPageType(v) {
+					// Let generators document the pagination pattern, ie. in OpenAPI.
+					structType.Struct.Type.TypeExtra.Meta = append(structType.Struct.Type.TypeExtra.Meta,
+						schema.TypeFieldMeta{"pagination": "cursor"},
+					)
+				}
+				return structType, nil
+			}
+
 			return p.ParseNamedType(goTypeName, underlying)
 		}
 
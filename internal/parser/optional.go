@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"go/types"
+)
+
+const gospeakPkgPath = "github.com/golang-cz/gospeak"
+
+// Unwraps gospeak.Optional[T] and gospeak.Nullable[T] wrapper types, including
+// nested Optional[Nullable[T]], and returns the underlying type T.
+//
+//	gospeak.Optional[T] => optional (field can be absent), but not nullable
+//	gospeak.Nullable[T] => nullable (field can be null), but not optional
+func unwrapOptional(typ types.Type) (elem types.Type, optional bool, nullable bool, ok bool) {
+	elem = types.Unalias(typ)
+	for {
+		named, isNamed := elem.(*types.Named)
+		if !isNamed || named.TypeArgs().Len() != 1 {
+			return elem, optional, nullable, ok
+		}
+
+		obj := named.Origin().Obj()
+		if obj.Pkg() == nil || obj.Pkg().Path() != gospeakPkgPath {
+			return elem, optional, nullable, ok
+		}
+
+		switch obj.Name() {
+		case "Optional":
+			optional = true
+		case "Nullable":
+			nullable = true
+		default:
+			return elem, optional, nullable, ok
+		}
+
+		ok = true
+		elem = types.Unalias(named.TypeArgs().At(0))
+	}
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Returns true if the given type is gospeak.Page[T] or a pointer to it.
+func isPageType(typ types.Type) bool {
+	return isGospeakType(typ, "Page")
+}
+
+// Returns true if the given type is gospeak.Cursor.
+func isCursorType(typ types.Type) bool {
+	return isGospeakType(typ, "Cursor")
+}
+
+func isGospeakType(typ types.Type, name string) bool {
+	if ptr, ok := types.Unalias(typ).(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Origin().Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == gospeakPkgPath && obj.Name() == name
+}
+
+// Annotates list methods returning gospeak.Page[T] as paginated, ie.:
+//
+//	ListPets(ctx context.Context, cursor gospeak.Cursor, limit int) (page *gospeak.Page[*Pet], err error)
+//
+// gets {"paginated": "cursor=cursor page=page"} annotation, so generated clients
+// can walk all pages by passing page.nextCursor back as the cursor argument.
+func paginationAnnotation(methodName string, params *types.Tuple, inputs []*schema.MethodArgument, results *types.Tuple, outputs []*schema.MethodArgument) (*schema.Annotation, error) {
+	var page string
+	for i, output := range outputs {
+		if isPageType(results.At(i).Type()) {
+			if page != "" {
+				return nil, fmt.Errorf("%v(): paginated method must return a single gospeak.Page", methodName)
+			}
+			page = output.Name
+		}
+	}
+	if page == "" {
+		return nil, nil
+	}
+
+	var cursor string
+	for i, input := range inputs {
+		if isCursorType(params.At(i + 1).Type()) { // skip context.Context
+			if cursor != "" {
+				return nil, fmt.Errorf("%v(): paginated method must accept a single gospeak.Cursor argument", methodName)
+			}
+			cursor = input.Name
+		}
+	}
+	if cursor == "" {
+		return nil, fmt.Errorf("%v(): paginated method must accept gospeak.Cursor argument, ie. ListPets(ctx context.Context, cursor gospeak.Cursor)", methodName)
+	}
+
+	return &schema.Annotation{
+		AnnotationType: "paginated",
+		Value:          fmt.Sprintf("cursor=%v page=%v", cursor, page),
+	}, nil
+}
+
+// Returns name of generic type instance, named after its type arguments, ie.
+// PetPage for gospeak.Page[*Pet] or StringUserMapResult for Result[map[string]User].
+func (p *Parser) genericTypeName(named *types.Named) (string, error) {
+	var b strings.Builder
+	for i := 0; i < named.TypeArgs().Len(); i++ {
+		varType, err := p.ParseNamedType("", named.TypeArgs().At(i))
+		if err != nil {
+			return "", fmt.Errorf("parsing type argument of %v: %w", named, err)
+		}
+		b.WriteString(typeArgName(varType))
+	}
+	b.WriteString(named.Obj().Name())
+
+	return b.String(), nil
+}
+
+func typeArgName(varType *schema.VarType) string {
+	switch varType.Type {
+	case schema.T_List:
+		return typeArgName(varType.List.Elem) + "List"
+	case schema.T_Map:
+		return typeArgName(varType.Map.Key) + typeArgName(varType.Map.Value) + "Map"
+	}
+	return firstToUpper(varType.Expr)
+}
@@ -1,7 +1,10 @@
 package parser
 
 import (
+	"go/ast"
+	"go/token"
 	"go/types"
+	"maps"
 
 	"github.com/webrpc/webrpc/schema"
 	"golang.org/x/tools/go/packages"
@@ -19,6 +22,29 @@ type Parser struct {
 
 	ParsedEnumTypes map[string]*schema.Type // Helps lookup enum types by pkg easily.
 
+	// TypeMappings maps named Go types (ie. github.com/shopspring/decimal.Decimal) to webrpc types (ie. string).
+	TypeMappings map[string]string
+
+	// TypeNames is the naming strategy of types declared outside of the schema package, see TypeNamesPackage.
+	TypeNames string
+
+	// ExportConstants adds the constants exported by //gospeak:export directive to the schema, see CollectConstants().
+	ExportConstants bool
+
+	// TypeNameOwners maps webrpc type names to the fully qualified Go types (ie. github.com/acme/app/models.User),
+	// so we can detect type name collisions.
+	TypeNameOwners map[string]string
+
+	externalTypes map[string]map[string]struct{} // Go types declared outside of the schema package by their type names, see externalTypeNames().
+
+	typeDecls map[*schema.Type]typeDecl // Declarations of schema types, see SortTypes().
+
+	pendingVariants map[types.Type][]func(*schema.VarType) error // Union variants being parsed up the stack, see ParseUnion().
+
+	astFields map[token.Pos]*ast.Field // Interface methods and struct fields of the schema package by position, see astField().
+
+	typeDocs map[token.Pos]*ast.CommentGroup // Doc comments of the schema package type declarations by position, see typeDoc().
+
 	InlineMode    bool // When traversing `json:",inline"`, we don't want to store the struct type as WebRPC message.
 	ImportedPaths map[string]struct{}
 
@@ -38,8 +64,12 @@ func New(pkg *packages.Package) *Parser {
 		ParsedTypes:     map[types.Type]*schema.VarType{},
 		Pkg:             pkg,
 		ParsedEnumTypes: map[string]*schema.Type{},
+		TypeMappings:    maps.Clone(DefaultTypeMappings),
+		TypeNames:       TypeNamesPackage,
+		TypeNameOwners:  map[string]string{},
+		typeDecls:       map[*schema.Type]typeDecl{},
+		pendingVariants: map[types.Type][]func(*schema.VarType) error{},
 
-		// TODO: Change this to map[*types.Package]string so we can rename duplicated pkgs?
 		ImportedPaths: map[string]struct{}{
 			// Initial schema file's package name artificially set by golang.org/x/tools/go/packages.
 			"command-line-arguments": {},
@@ -19,6 +19,7 @@ type JsonTag struct {
 	Value     string
 	IsString  bool
 	Omitempty bool
+	Omitzero  bool // Go 1.24+
 	Inline    bool
 }
 
@@ -42,6 +43,7 @@ func GetJsonTag(structTags string) (JsonTag, bool) {
 		Value:     submatches[1] + submatches[2],
 		IsString:  strings.Contains(submatches[2], ",string"),
 		Omitempty: strings.Contains(submatches[2], ",omitempty"),
+		Omitzero:  strings.Contains(submatches[2], ",omitzero"),
 		Inline:    strings.Contains(submatches[2], ",inline"),
 	}
 
@@ -69,6 +71,23 @@ func isTextMarshaler(typ types.Type, pkg *types.Package) bool {
 var jsonMarshalerRegex = regexp.MustCompile(`^func \((.+)\)\.MarshalJSON\(\) \((.+ )?\[\]byte, ([a-z]+ )?error\)$`)
 var jsonUnmarshalerRegex = regexp.MustCompile(`^func \((.+)\)\.UnmarshalJSON\((.+ )?\[\]byte\) \(?(.+ )?error\)?$`)
 
+// Returns true if the given type has IsZero() bool method, which is used by `json:",omitzero"`.
+func hasIsZeroMethod(typ types.Type, pkg *types.Package) bool {
+	isZeroMethod, _, _ := types.LookupFieldOrMethod(typ, true, pkg, "IsZero")
+	fn, ok := isZeroMethod.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+
+	basic, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && basic.Kind() == types.Bool
+}
+
 // Returns true if the given type implements json.Marshaler/Unmarshaler interfaces.
 func isJsonMarshaller(typ types.Type, pkg *types.Package) bool {
 	marshalJsonMethod, _, _ := types.LookupFieldOrMethod(typ, true, pkg, "MarshalJSON")
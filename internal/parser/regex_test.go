@@ -22,6 +22,8 @@ func TestJsonTagRegex(t *testing.T) {
 		{in: `json:"id,omitempty,string"`, out: JsonTag{Name: "id", Value: "id,omitempty,string", IsString: true, Omitempty: true}},
 		{in: `json:"id,string,omitempty"`, out: JsonTag{Name: "id", Value: "id,string,omitempty", IsString: true, Omitempty: true}},
 		{in: `json:"ID,string,omitempty"`, out: JsonTag{Name: "ID", Value: "ID,string,omitempty", IsString: true, Omitempty: true}},
+		{in: `json:"id,omitzero"`, out: JsonTag{Name: "id", Value: "id,omitzero", Omitzero: true}},
+		{in: `json:"id,omitempty,omitzero"`, out: JsonTag{Name: "id", Value: "id,omitempty,omitzero", Omitempty: true, Omitzero: true}},
 		{in: `json:"renamed_fieldName99"`, out: JsonTag{Name: "renamed_fieldName99", Value: "renamed_fieldName99"}},
 		{in: `xxx:"X X X" json:"id,string" yyy:"Y Y Y"`, out: JsonTag{Name: "id", Value: "id,string", IsString: true}},
 		{in: `db:"id,omitempty,pk" json:"id,string"`, out: JsonTag{Name: "id", Value: "id,string", IsString: true}},
@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// HTTP methods allowed by //gospeak:route directive.
+var routeMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
+var routeParamRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// Parses and validates //gospeak:route directive, ie.:
+//
+//	//gospeak:route GET /pets/{ID}
+//	GetPet(ctx context.Context, ID int64) (*Pet, error)
+//
+// The route uses net/http.ServeMux pattern syntax (Go 1.22+), so generated servers
+// can register it as is. Path params bind to input arguments of the same name and
+// must be of scalar types. Other arguments, unless bound to headers by //gospeak:arg,
+// bind to query params of GET, HEAD and DELETE routes, so they must be scalars or
+// lists of scalars, or to JSON body of the other routes. Query param names must be
+// unique and distinct from path params. Returns normalized route, ie. "GET /pets/{ID}".
+func parseRoute(value string, inputs []*schema.MethodArgument) (string, error) {
+	parts := strings.Fields(value)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("expected <HTTP method> <path>, ie. GET /pets/{ID}")
+	}
+
+	method, path := strings.ToUpper(parts[0]), parts[1]
+	if !slices.Contains(routeMethods, method) {
+		return "", fmt.Errorf("unsupported HTTP method %v: expected one of %v", parts[0], strings.Join(routeMethods, ", "))
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("path %v must start with /", path)
+	}
+
+	pathParams := map[string]bool{}
+	segments := strings.Split(path[1:], "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+
+		// Exact match of the path with trailing slash, ie. GET /pets/{$} doesn't match /pets/1.
+		if segment == "{$}" {
+			if i != len(segments)-1 {
+				return "", fmt.Errorf("path end {$} must be the last path segment")
+			}
+			continue
+		}
+
+		match := routeParamRegex.FindStringSubmatch(segment)
+		if match == nil || match[0] != segment {
+			return "", fmt.Errorf("path param %v must be a whole path segment, ie. /pets/{ID}", segment)
+		}
+
+		name, wildcard := strings.CutSuffix(match[1], "...")
+		if wildcard && i != len(segments)-1 {
+			return "", fmt.Errorf("wildcard path param {%v...} must be the last path segment", name)
+		}
+		if !isIdentifier(name) {
+			return "", fmt.Errorf("invalid path param {%v}: expected method argument name", match[1])
+		}
+		if pathParams[name] {
+			return "", fmt.Errorf("duplicate path param {%v}", name)
+		}
+		pathParams[name] = true
+
+		arg := findArgument(inputs, name)
+		if arg == nil {
+			return "", fmt.Errorf("path param {%v} doesn't match any method argument", name)
+		}
+		if arg.Optional {
+			return "", fmt.Errorf("path param {%v} can't bind to optional argument", name)
+		}
+		if hasMeta(arg.TypeExtra.Meta, "header") || hasMeta(arg.TypeExtra.Meta, "query") {
+			return "", fmt.Errorf("path param {%v} can't bind to argument bound by //gospeak:arg", name)
+		}
+		if !isScalarType(arg.Type) || (wildcard && arg.Type.Type != schema.T_String) {
+			return "", fmt.Errorf("path param {%v} can't bind to argument of type %v", match[1], arg.Type.Expr)
+		}
+	}
+
+	// Query params are bound by //gospeak:arg query=<name> and, on GET, HEAD and DELETE
+	// routes, by the names of the other arguments. The names must be unique.
+	queryParams := map[string]string{} // lowercase query param name => argument
+	for _, arg := range inputs {
+		if pathParams[arg.Name] || hasMeta(arg.TypeExtra.Meta, "header") {
+			continue
+		}
+
+		name, bound := queryParam(arg)
+		if !bound {
+			if method != "GET" && method != "HEAD" && method != "DELETE" {
+				continue // JSON body
+			}
+			argType := arg.Type
+			if argType.Type == schema.T_List {
+				argType = argType.List.Elem
+			}
+			if !isScalarType(argType) {
+				return "", fmt.Errorf("argument %v of type %v can't bind to query params of %v route: use path param, scalar type or POST method", arg.Name, arg.Type.Expr, method)
+			}
+			name = arg.Name
+		}
+
+		for param := range pathParams {
+			if strings.EqualFold(param, name) {
+				return "", fmt.Errorf("query param %v of argument %v conflicts with path param {%v}", name, arg.Name, param)
+			}
+		}
+		if other, ok := queryParams[strings.ToLower(name)]; ok {
+			return "", fmt.Errorf("query param %v of argument %v conflicts with argument %v", name, arg.Name, other)
+		}
+		queryParams[strings.ToLower(name)] = arg.Name
+	}
+
+	return method + " " + path, nil
+}
+
+// Returns the route pattern with path param names removed, so we can detect
+// conflicting routes, ie. "GET /pets/{ID}" and "GET /pets/{petID}".
+func routeKey(route string) string {
+	return routeParamRegex.ReplaceAllStringFunc(route, func(param string) string {
+		if param == "{$}" {
+			return param
+		}
+		if strings.HasSuffix(param, "...}") {
+			return "{...}"
+		}
+		return "{}"
+	})
+}
+
+// Returns the method with the same route, if any.
+func findRoute(services []*schema.Service, route string) (*schema.Service, *schema.Method) {
+	for _, service := range services {
+		for _, method := range service.Methods {
+			if annotation, ok := method.Annotations["route"]; ok && routeKey(annotation.Value) == routeKey(route) {
+				return service, method
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Returns the query param name the argument is bound to by //gospeak:arg, if any.
+func queryParam(arg *schema.MethodArgument) (string, bool) {
+	for _, meta := range arg.TypeExtra.Meta {
+		if name, ok := meta["query"].(string); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func findArgument(args []*schema.MethodArgument, name string) *schema.MethodArgument {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+// Returns true for types encoded as a single string in URL path or query, ie. int64 or enum.
+func isScalarType(varType *schema.VarType) bool {
+	switch varType.Type {
+	case schema.T_String, schema.T_Bool, schema.T_Timestamp,
+		schema.T_Int, schema.T_Int8, schema.T_Int16, schema.T_Int32, schema.T_Int64,
+		schema.T_Uint, schema.T_Uint8, schema.T_Uint16, schema.T_Uint32, schema.T_Uint64,
+		schema.T_Float32, schema.T_Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Validates the method signature, ie.:
+//
+//	GetPet(ctx context.Context, ID int64) (pet *Pet, err error)
+//
+// The first argument must be context.Context and the last return value must be error.
+// Reports all problems found in the signature, each with a suggested fix.
+func (p *Parser) validateMethodSignature(method *types.Func, signature *types.Signature) error {
+	methodName := method.Name()
+	params := signature.Params()
+	results := signature.Results()
+
+	var diagnostics Diagnostics
+
+	// First method argument must be of type context.Context.
+	if params.Len() == 0 {
+		diagnostic := p.errorf(method.Pos(), "%v(): first argument must be context.Context: no arguments defined", methodName)
+		diagnostic.Fix = "add ctx context.Context as the first argument"
+		diagnostics = append(diagnostics, diagnostic)
+	} else if first := params.At(0); !isContextType(first.Type()) {
+		diagnostic := p.errorf(first.Pos(), "%v(): first argument must be context.Context: found %v", methodName, first.Type())
+		diagnostic.Fix = fmt.Sprintf("replace the first argument %v %v with ctx context.Context", argumentName(first, true), types.TypeString(first.Type(), p.qualifier))
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	// Last method return value must be of type error.
+	if results.Len() == 0 {
+		diagnostic := p.errorf(method.Pos(), "%v(): last return value must be error: no return values defined", methodName)
+		diagnostic.Fix = "add err error as the last return value"
+		diagnostics = append(diagnostics, diagnostic)
+	} else if last := results.At(results.Len() - 1); !isErrorType(last.Type()) {
+		diagnostic := p.errorf(last.Pos(), "%v(): last return value must be error: found %v", methodName, last.Type())
+		diagnostic.Fix = "add err error as the last return value"
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	if signature.Variadic() {
+		last := params.At(params.Len() - 1)
+		elem := last.Type().(*types.Slice).Elem()
+		diagnostic := p.errorf(last.Pos(), "%v(): variadic argument %v ...%v is not supported", methodName, last.Name(), elem)
+		diagnostic.Fix = fmt.Sprintf("use a slice instead: %v []%v", argumentName(last, true), elem)
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	diagnostics = append(diagnostics, p.validateArgumentNames(methodName, params, true)...)
+	diagnostics = append(diagnostics, p.validateArgumentNames(methodName, results, false)...)
+
+	if len(diagnostics) > 0 {
+		diagnostics.Sort()
+		return diagnostics
+	}
+
+	return nil
+}
+
+// Validates names of method arguments or return values, excluding the context.Context
+// argument and error return value, which are not part of the schema.
+func (p *Parser) validateArgumentNames(methodName string, params *types.Tuple, isInput bool) Diagnostics {
+	var diagnostics Diagnostics
+
+	kind := "argument"
+	if !isInput {
+		kind = "return value"
+	}
+
+	// Names are compared case-insensitively, since the generated code capitalizes them.
+	names := map[string]*types.Var{}
+
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+
+		if isInput && i == 0 && isContextType(param.Type()) {
+			continue
+		}
+		if !isInput && i == params.Len()-1 && isErrorType(param.Type()) {
+			continue
+		}
+
+		if isContextType(param.Type()) {
+			diagnostic := p.errorf(param.Pos(), "%v(): context.Context is allowed only as the first argument", methodName)
+			diagnostic.Fix = fmt.Sprintf("remove %v %v", kind, param.Type())
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+		if isErrorType(param.Type()) {
+			diagnostic := p.errorf(param.Pos(), "%v(): error is allowed only as the last return value", methodName)
+			diagnostic.Fix = fmt.Sprintf("remove %v %v", kind, param.Type())
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+
+		if param.Name() == "_" {
+			diagnostic := p.errorf(param.Pos(), "%v(): blank %v name _ is not supported", methodName, kind)
+			diagnostic.Fix = fmt.Sprintf("name the %v, ie. %v %v", kind, argumentName(param, isInput), param.Type())
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+
+		name := argumentName(param, isInput)
+		if prev, ok := names[strings.ToLower(name)]; ok {
+			diagnostic := p.errorf(param.Pos(), "%v(): duplicate %v name %q", methodName, kind, name)
+			if param.Name() == "" || prev.Name() == "" {
+				diagnostic.Msg += " generated from unnamed " + kind + "s"
+			}
+			diagnostic.Fix = fmt.Sprintf("give each %v a unique name", kind)
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+		names[strings.ToLower(name)] = param
+	}
+
+	return diagnostics
+}
+
+// Returns the method argument name. If the argument's name is not defined,
+// comes up with a name based on its type, ie.:
+//
+//	*pkg.User => user
+//	[]*pkg.User => userList
+//	[]string => stringList
+//
+// Input arguments get "Req" suffix.
+func argumentName(param *types.Var, isInput bool) string {
+	if name := param.Name(); name != "" && name != "_" {
+		return name
+	}
+
+	typ := param.Type()
+
+	name := typ.String()
+	name = name[findFirstLetter(name):]
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[i+1:]
+	}
+	name = firstToLower(name)
+
+	switch typ.(type) {
+	case *types.Slice, *types.Array:
+		name += "List"
+	}
+
+	if isInput {
+		name += "Req"
+	}
+
+	return name
+}
+
+// Returns true if the given type is exactly context.Context.
+func isContextType(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// Returns true if the given type is exactly the predeclared error type.
+func isErrorType(typ types.Type) bool {
+	return types.Identical(typ, types.Universe.Lookup("error").Type())
+}
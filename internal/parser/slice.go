@@ -8,6 +8,14 @@ import (
 )
 
 func (p *Parser) ParseSlice(typeName string, sliceTyp *types.Slice) (*schema.VarType, error) {
+	// []byte is encoded as base64 string by encoding/json.
+	if isByteSlice(sliceTyp) {
+		return &schema.VarType{
+			Expr: "string",
+			Type: schema.T_String,
+		}, nil
+	}
+
 	elem, err := p.ParseNamedType(typeName, sliceTyp.Elem())
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse slice type: %w", err)
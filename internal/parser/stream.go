@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"go/types"
+)
+
+// Finds server-streaming return value, ie.:
+//
+//	WatchPets(ctx context.Context) (<-chan *Pet, error)
+//	WatchPets(ctx context.Context) (iter.Seq[*Pet], error)
+//	WatchPets(ctx context.Context) (iter.Seq2[*Pet, error], error)
+//
+// If found, returns results with the stream replaced by its element type, so the method
+// outputs describe a single message of the stream. The stream element keeps the original
+// return value name, if any.
+func (p *Parser) streamResults(methodName string, results *types.Tuple) (*types.Tuple, bool, error) {
+	var (
+		stream *types.Var
+		elem   types.Type
+	)
+
+	for i := 0; i < results.Len(); i++ {
+		result := results.At(i)
+
+		resultElem, ok, err := p.streamElem(methodName, result)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+		if stream != nil {
+			return nil, false, p.errorf(result.Pos(), "%v(): server-streaming method must return a single stream", methodName)
+		}
+		stream, elem = result, resultElem
+	}
+
+	if stream == nil {
+		return results, false, nil
+	}
+
+	if results.Len() != 2 || results.At(0) != stream {
+		diagnostic := p.errorf(stream.Pos(), "%v(): server-streaming method must return exactly (stream, error)", methodName)
+		diagnostic.Fix = "move other return values into the stream element type"
+		return nil, false, diagnostic
+	}
+
+	elemVar := types.NewVar(stream.Pos(), stream.Pkg(), stream.Name(), elem)
+	if elemVar.Name() == "" {
+		elemVar = types.NewVar(stream.Pos(), stream.Pkg(), argumentName(elemVar, false), elem)
+	}
+
+	return types.NewTuple(elemVar, results.At(1)), true, nil
+}
+
+// Returns the element type of a channel or iterator, ie. <-chan *Pet or iter.Seq2[*Pet, error].
+func (p *Parser) streamElem(methodName string, result *types.Var) (types.Type, bool, error) {
+	switch typ := types.Unalias(result.Type()).(type) {
+	case *types.Chan:
+		if typ.Dir() == types.SendOnly {
+			diagnostic := p.errorf(result.Pos(), "%v(): send-only channel %v can't be used as a stream", methodName, typ)
+			diagnostic.Fix = "return a receive-only channel <-chan " + typ.Elem().String()
+			return nil, false, diagnostic
+		}
+		return typ.Elem(), true, nil
+
+	case *types.Named:
+		obj := typ.Obj()
+		if obj.Pkg() == nil || obj.Pkg().Path() != "iter" {
+			return nil, false, nil
+		}
+
+		typeArgs := typ.TypeArgs()
+		switch obj.Name() {
+		case "Seq":
+			return typeArgs.At(0), true, nil
+
+		case "Seq2":
+			if !isErrorType(typeArgs.At(1)) {
+				diagnostic := p.errorf(result.Pos(), "%v(): stream %v must yield error as the second value", methodName, typ)
+				diagnostic.Fix = "return iter.Seq2[" + typeArgs.At(0).String() + ", error]"
+				return nil, false, diagnostic
+			}
+			return typeArgs.At(0), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
@@ -10,47 +10,98 @@ import (
 func (p *Parser) ParseStruct(goTypeName string, structTyp *types.Struct) (*schema.VarType, error) {
 	webrpcTypeName := p.GoTypeNameToWebrpc(goTypeName)
 
+	// Anonymous structs of the same name are parsed into a single schema type, if they're identical.
+	goType := types.TypeString(structTyp, nil)
+	if owner, ok := p.TypeNameOwners[webrpcTypeName]; ok {
+		if owner != goType {
+			return nil, fmt.Errorf("type name %v of anonymous %v collides with %v: name the struct by `gospeak:\"typename=<Name>\"` tag", webrpcTypeName, goType, owner)
+		}
+		if existing := p.Schema.GetTypeByName(webrpcTypeName); existing != nil {
+			return &schema.VarType{
+				Expr: webrpcTypeName,
+				Type: schema.T_Struct,
+				Struct: &schema.VarStructType{
+					Name: webrpcTypeName,
+					Type: existing,
+				},
+			}, nil
+		}
+	}
+	p.TypeNameOwners[webrpcTypeName] = goType
+
+	var decl types.Object
+	if structTyp.NumFields() > 0 {
+		decl = structTyp.Field(0) // anonymous struct is declared where its first field is
+	}
+	return p.parseStruct(webrpcTypeName, goTypeName, structTyp, decl)
+}
+
+// Returns true if the given type is an anonymous struct, or a pointer, slice, array or map of it.
+func hasAnonymousStruct(typ types.Type) bool {
+	for {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Struct:
+			return true
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		case *types.Array:
+			typ = t.Elem()
+		case *types.Map:
+			typ = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+// Parses struct into webrpc type of the given name. The decl is used to sort schema types, see SortTypes().
+func (p *Parser) parseStruct(webrpcTypeName string, goTypeName string, structTyp *types.Struct, decl types.Object) (*schema.VarType, error) {
 	structType := &schema.Type{
 		Kind: "struct",
 		Name: webrpcTypeName,
 	}
 
-	for i := 0; i < structTyp.NumFields(); i++ {
-		structField := structTyp.Field(i)
-		if !structField.Exported() {
+	// Collect errors from all fields, so we can report them at once.
+	fields, diagnostics := p.collectStructFields(structTyp)
+
+	for _, f := range fields {
+		field, err := p.parseStructField(webrpcTypeName, f.field, f.jsonTag, f.gospeakTag)
+		if err != nil {
+			diagnostics = append(diagnostics, p.diagnose(f.field.Pos(), fmt.Errorf("parsing struct field %v: %w", f.field.Name(), err))...)
 			continue
 		}
-		structTags := structTyp.Tag(i)
-
-		jsonTag, _ := GetJsonTag(structTags)
-		if jsonTag.Name == "-" { // struct field ignored by `json:"-"` struct tag
+		if field == nil {
 			continue
 		}
-
-		if structField.Embedded() || jsonTag.Inline {
-			varType, err := p.ParseNamedType("", structField.Type())
-			if err != nil {
-				return nil, fmt.Errorf("parsing var %v: %w", structField.Name(), err)
-			}
-
-			if varType.Type == schema.T_Struct {
-				for _, embeddedField := range varType.Struct.Type.Fields {
-					structType.Fields = appendOrOverrideExistingField(structType.Fields, embeddedField)
-				}
-			}
+		if f.optional && !f.gospeakTag.Required {
+			// Promoted from embedded pointer, which is omitted by encoding/json if nil.
+			field.TypeExtra.Optional = true
+		}
+		applyValidationRules(field, f.validateTag, f.gospeakTag.Rules)
+		if directive, ok := sensitiveDirective(p.fieldDirectives(f.field)); ok {
+			// Comments are not visible at runtime, so gospeak.Redact() would log the value.
+			diagnostic := p.errorf(directive.Pos, "struct field %v: //gospeak:sensitive comment is not supported, since gospeak.Redact() can't see it", f.field.Name())
+			diagnostic.Fix = "use `gospeak:\"sensitive\"` struct tag"
+			diagnostics = append(diagnostics, diagnostic)
 			continue
 		}
-
-		field, err := p.parseStructField(goTypeName+"Field", structField, jsonTag)
-		if err != nil {
-			return nil, fmt.Errorf("parsing struct field %v: %w", i, err)
+		if f.gospeakTag.Sensitive {
+			markSensitive(field)
 		}
-		if field != nil {
-			structType.Fields = appendOrOverrideExistingField(structType.Fields, field)
+		if err := p.applyExamples(field, f.field, f.structTag); err != nil {
+			diagnostics = append(diagnostics, p.diagnose(f.field.Pos(), fmt.Errorf("parsing struct field %v: %w", f.field.Name(), err))...)
+			continue
 		}
+		structType.Fields = append(structType.Fields, field)
+	}
+
+	if len(diagnostics) > 0 {
+		return nil, diagnostics
 	}
 
-	p.Schema.Types = append(p.Schema.Types, structType)
+	p.addType(structType, decl)
 
 	return &schema.VarType{
 		Expr: webrpcTypeName,
@@ -64,7 +115,7 @@ func (p *Parser) ParseStruct(goTypeName string, structTyp *types.Struct) (*schem
 
 // parses single Go struct field
 // if the field is embedded, ie. `json:",inline"`, parse recursively
-func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonTag JsonTag) (*schema.TypeField, error) {
+func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonTag JsonTag, gospeakTag GospeakTag) (*schema.TypeField, error) {
 	fieldName := field.Name()
 	fieldType := field.Type()
 
@@ -74,6 +125,17 @@ func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonT
 
 	goFieldImport := p.GoTypeImport(fieldType)
 
+	// Optional and nullable wrappers, ie. gospeak.Optional[int64] or gospeak.Nullable[string].
+	elemType, optionalWrapper, nullable, wrapped := unwrapOptional(fieldType)
+	var elemImport string
+	if wrapped {
+		fieldType = elemType
+		optional = optionalWrapper
+		if elemImport = p.GoTypeImport(elemType); elemImport == goFieldImport {
+			elemImport = ""
+		}
+	}
+
 	if jsonTag.Name != "" {
 		if jsonTag.Name == "-" { // struct field ignored by `json:"-"` struct tag
 			return nil, nil
@@ -81,9 +143,22 @@ func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonT
 		jsonFieldName = jsonTag.Name
 	}
 
+	// Absent Optional is encoded as null, which its UnmarshalJSON() rejects.
+	if optionalWrapper && !jsonTag.Omitzero {
+		diagnostic := p.errorf(field.Pos(), "optional field %v must be omitted from JSON if absent", fieldName)
+		diagnostic.Fix = fmt.Sprintf("add omitzero option, ie. `json:\"%v,omitzero\"`", jsonFieldName)
+		return nil, diagnostic
+	}
+
 	if jsonTag.Omitempty {
 		optional = jsonTag.Omitempty
-		goFieldType = "*" + goFieldType
+		if !wrapped {
+			goFieldType = "*" + goFieldType
+		}
+	}
+
+	if jsonTag.Omitzero { // Go 1.24+, the field is omitted if it's zero value or its IsZero() method returns true
+		optional = true
 	}
 
 	if jsonTag.IsString { // struct field forced to be string by `json:",string"`
@@ -110,23 +185,44 @@ func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonT
 			schema.TypeFieldMeta{"go.tag.json": jsonTag.Value},
 		)
 
+		if err := p.applyGospeakTag(structField, gospeakTag); err != nil {
+			return nil, err
+		}
+
 		return structField, nil
 	}
 
-	if _, ok := fieldType.Underlying().(*types.Pointer); ok {
+	if _, ok := fieldType.Underlying().(*types.Pointer); ok && !wrapped {
 		optional = true
 		goFieldType = "*" + goFieldType
 	}
 
-	if _, ok := fieldType.Underlying().(*types.Struct); ok {
-		// Anonymous struct fields.
+	varTypeName := goFieldType
+	if wrapped {
+		varTypeName = p.GoTypeName(fieldType)
+	}
+
+	if hasAnonymousStruct(fieldType) {
+		// Anonymous struct fields are named after the parent type and the field,
+		// unless named explicitly by `gospeak:"typename=<Name>"` struct tag.
 		// Example:
-		//   type Something struct {
-		// 	   AnonymousField struct { // no explicit struct type name
+		//   type Pet struct {
+		// 	   Meta struct { // PetMeta
+		//       Name string
+		//     }
+		//     Tags []struct { // PetTags
 		//       Name string
 		//     }
 		//   }
-		structTypeName = /*structTypeName + */ "Anonymous" + field.Name()
+		varTypeName = structTypeName + field.Name()
+		if gospeakTag.TypeName != "" {
+			if !isIdentifier(gospeakTag.TypeName) {
+				return nil, fmt.Errorf("invalid gospeak tag typename=%v: expected identifier", gospeakTag.TypeName)
+			}
+			varTypeName = gospeakTag.TypeName
+		}
+	} else if gospeakTag.TypeName != "" {
+		return nil, fmt.Errorf("gospeak tag typename=%v is allowed only on anonymous struct fields", gospeakTag.TypeName)
 	}
 
 	// TODO: Can we ever see type aliases here? If so, how do you trigger this?
@@ -143,7 +239,7 @@ func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonT
 		}
 	}
 
-	varType, err := p.ParseNamedType(goFieldType, fieldType)
+	varType, err := p.ParseNamedType(varTypeName, fieldType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse var %v: %w", field.Name(), err)
 	}
@@ -164,24 +260,135 @@ func (p *Parser) parseStructField(structTypeName string, field *types.Var, jsonT
 			schema.TypeFieldMeta{"go.type.import": goFieldImport},
 		)
 	}
+	if jsonTag.Omitzero {
+		// Let the Go generator keep the omitzero option with a value (non-pointer) field type.
+		zero := "zero"
+		if hasIsZeroMethod(field.Type(), field.Pkg()) {
+			zero = "IsZero"
+		}
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"go.field.omitzero": zero})
+	}
+	if elemImport != "" {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta,
+			schema.TypeFieldMeta{"go.type.import": elemImport},
+		)
+	}
 	if jsonTag.Value != "" {
 		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"go.tag.json": jsonTag.Value})
 	}
+	if m, ok := fieldType.Underlying().(*types.Map); ok {
+		if enum := p.mapKeyEnum(m.Key()); enum != "" {
+			// Let generators emit typed map keys, ie. Record<Status, T> in TypeScript.
+			structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"map.key.enum": enum})
+		}
+	}
+	if nullable {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"nullable": true})
+	}
+	if format := typeFormat(fieldType); format != "" {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"format": format})
+	}
+
+	if err := p.applyGospeakTag(structField, gospeakTag); err != nil {
+		return nil, err
+	}
 
 	return structField, nil
 }
 
-// Appends message field to the given slice, while also removing any previously defined field of the same name.
-// This lets us overwrite embedded fields, exactly how Go does it behind the scenes in the JSON marshaller.
-func appendOrOverrideExistingField(slice []*schema.TypeField, newItem *schema.TypeField) []*schema.TypeField {
-	// Let's try to find an existing item of the same name and delete it.
-	for i, item := range slice {
-		if item.Name == newItem.Name {
-			// Delete item.
-			copy(slice[i:], slice[i+1:])
-			slice = slice[:len(slice)-1]
+// Applies `gospeak:"..."` struct tag overrides to the parsed struct field.
+func (p *Parser) applyGospeakTag(structField *schema.TypeField, tag GospeakTag) error {
+	if tag.Value == "" {
+		return nil
+	}
+
+	if tag.Name != "" && tag.Name != structField.Name {
+		// Keep the JSON field name on the wire, since the server encodes the original Go struct.
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"json": structField.Name})
+		if !hasMeta(structField.TypeExtra.Meta, "go.tag.json") {
+			structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"go.tag.json": structField.Name})
+		}
+		structField.Name = tag.Name
+	}
+
+	if tag.Type != "" {
+		var varType schema.VarType
+		if err := schema.ParseVarTypeExpr(p.Schema, tag.Type, &varType); err != nil {
+			return fmt.Errorf("invalid gospeak tag type=%v: %w", tag.Type, err)
+		}
+		structField.Type = &varType
+	}
+
+	if tag.Optional {
+		structField.TypeExtra.Optional = true
+	}
+	if tag.Required {
+		structField.TypeExtra.Optional = false
+	}
+
+	if tag.ReadOnly {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"readonly": true})
+	}
+	if tag.WriteOnly {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"writeonly": true})
+	}
+	if tag.Deprecated {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"deprecated": true})
+	}
+
+	structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"go.tag.gospeak": tag.Value})
+
+	return nil
+}
+
+// Returns //gospeak:sensitive directive of the field, if any.
+func sensitiveDirective(directives []Directive) (Directive, bool) {
+	for _, directive := range directives {
+		if directive.Name == "sensitive" {
+			return directive, true
+		}
+	}
+	return Directive{}, false
+}
+
+// Marks sensitive field, ie. password or token, by `gospeak:"sensitive"` struct tag.
+// Sensitive strings get OpenAPI "password" format,
+// unless they already have a format, and the fields are write-only, unless they're read-only, ie. tokens issued by the server.
+func markSensitive(structField *schema.TypeField) {
+	structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"sensitive": true})
+
+	// Keep more specific formats, ie. uuid.
+	if structField.Type != nil && structField.Type.Type == schema.T_String && !hasMeta(structField.TypeExtra.Meta, "format") {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"format": "password"})
+	}
+
+	if !hasMeta(structField.TypeExtra.Meta, "readonly") && !hasMeta(structField.TypeExtra.Meta, "writeonly") {
+		structField.TypeExtra.Meta = append(structField.TypeExtra.Meta, schema.TypeFieldMeta{"writeonly": true})
+	}
+}
+
+// Returns the field of the given JSON name, which differs from the field name
+// renamed by `gospeak:"name=..."` struct tag, see applyGospeakTag().
+func findJSONField(fields []*schema.TypeField, jsonName string) *schema.TypeField {
+	for _, field := range fields {
+		name := field.Name
+		for _, meta := range field.TypeExtra.Meta {
+			if value, ok := meta["json"].(string); ok {
+				name = value
+			}
+		}
+		if name == jsonName {
+			return field
+		}
+	}
+	return nil
+}
+
+func hasMeta(meta []schema.TypeFieldMeta, key string) bool {
+	for _, m := range meta {
+		if _, ok := m[key]; ok {
+			return true
 		}
 	}
-	// And then append the new item at the end of the slice.
-	return append(slice, newItem)
+	return false
 }
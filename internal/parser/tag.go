@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/golang-cz/gospeak/internal/rules"
+)
+
+// GospeakTag holds webrpc-specific field overrides, ie.:
+//
+//	`gospeak:"-"`                          // hidden from the API, but still present in JSON
+//	`gospeak:"name=petId,type=string"`     // field name in generated code, schema type override
+//	`gospeak:"optional,readonly"`          // field extras
+//	`gospeak:"sensitive"`                  // password, token etc. redacted from logs, see gospeak.Redact()
+//	`gospeak:"typename=PetTag"`            // type name of anonymous struct field
+//	`gospeak:"min=1,max=64,format=email"`  // validation rules, see rules.GospeakOption()
+type GospeakTag struct {
+	Value      string
+	Ignore     bool         // -
+	Name       string       // name=<field name>
+	Type       string       // type=<webrpc type>
+	TypeName   string       // typename=<anonymous struct type name>
+	Optional   bool         // optional
+	Required   bool         // required
+	ReadOnly   bool         // readonly
+	WriteOnly  bool         // writeonly
+	Deprecated bool         // deprecated
+	Sensitive  bool         // sensitive
+	Rules      []rules.Rule // min=<n>, max=<n>, len=<n>, pattern=<regexp>, format=<format>, oneof=<values>
+}
+
+// GetGospeakTag parses `gospeak:"..."` struct tag.
+func GetGospeakTag(structTags string) (GospeakTag, bool, error) {
+	value, ok := reflect.StructTag(structTags).Lookup("gospeak")
+	if !ok {
+		return GospeakTag{}, false, nil
+	}
+
+	tag := GospeakTag{Value: value}
+
+	if value == "-" {
+		tag.Ignore = true
+		return tag, true, nil
+	}
+
+	for _, option := range rules.SplitOptions(value) {
+		name, arg, hasArg := strings.Cut(option, "=")
+		switch name {
+		case "name":
+			tag.Name = arg
+		case "type":
+			tag.Type = arg
+		case "typename":
+			tag.TypeName = arg
+		case "optional":
+			tag.Optional = true
+		case "required":
+			tag.Required = true
+		case "readonly":
+			tag.ReadOnly = true
+		case "writeonly":
+			tag.WriteOnly = true
+		case "deprecated":
+			tag.Deprecated = true
+		case "sensitive":
+			tag.Sensitive = true
+		default:
+			rule, ok, err := rules.GospeakOption(name, arg)
+			if err != nil {
+				return GospeakTag{}, false, fmt.Errorf("invalid gospeak tag option %v", err)
+			}
+			if !ok {
+				return GospeakTag{}, false, fmt.Errorf("unknown gospeak tag option %q", option)
+			}
+			if !hasArg {
+				return GospeakTag{}, false, fmt.Errorf("gospeak tag option %q requires a value, ie. %v=<value>", name, name)
+			}
+			tag.Rules = append(tag.Rules, rule)
+		}
+
+		if hasArg && arg == "" {
+			return GospeakTag{}, false, fmt.Errorf("gospeak tag option %q requires a value", name)
+		}
+		if !hasArg && (name == "name" || name == "type" || name == "typename") {
+			return GospeakTag{}, false, fmt.Errorf("gospeak tag option %q requires a value, ie. %v=<value>", name, name)
+		}
+	}
+
+	if tag.Optional && tag.Required {
+		return GospeakTag{}, false, fmt.Errorf("gospeak tag options optional and required are mutually exclusive")
+	}
+	if tag.ReadOnly && tag.WriteOnly {
+		return GospeakTag{}, false, fmt.Errorf("gospeak tag options readonly and writeonly are mutually exclusive")
+	}
+
+	return tag, true, nil
+}
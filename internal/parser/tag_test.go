@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/golang-cz/gospeak/internal/rules"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGospeakTag(t *testing.T) {
+	tt := []struct {
+		in  string
+		out GospeakTag
+		err bool
+	}{
+		{in: ``},
+		{in: `json:"id"`},
+		{in: `gospeak:"-"`, out: GospeakTag{Value: "-", Ignore: true}},
+		{in: `gospeak:"name=petId"`, out: GospeakTag{Value: "name=petId", Name: "petId"}},
+		{in: `gospeak:"type=string,optional"`, out: GospeakTag{Value: "type=string,optional", Type: "string", Optional: true}},
+		{in: `gospeak:"type=map<string,int64>,required"`, out: GospeakTag{Value: "type=map<string,int64>,required", Type: "map<string,int64>", Required: true}},
+		{in: `gospeak:"readonly, deprecated"`, out: GospeakTag{Value: "readonly, deprecated", ReadOnly: true, Deprecated: true}},
+		{in: `gospeak:"sensitive,writeonly"`, out: GospeakTag{Value: "sensitive,writeonly", WriteOnly: true, Sensitive: true}},
+		{in: `json:"id,omitempty" gospeak:"writeonly"`, out: GospeakTag{Value: "writeonly", WriteOnly: true}},
+		{in: `gospeak:"typename=PetTag"`, out: GospeakTag{Value: "typename=PetTag", TypeName: "PetTag"}},
+		{in: `gospeak:"min=1,max=64,format=email"`, out: GospeakTag{Value: "min=1,max=64,format=email", Rules: []rules.Rule{{Name: "min", Param: "1"}, {Name: "max", Param: "64"}, {Name: "email"}}}},
+		{in: `gospeak:"pattern=^[a-z]{10x2C3}$,oneof=asc desc"`, out: GospeakTag{Value: "pattern=^[a-z]{10x2C3}$,oneof=asc desc", Rules: []rules.Rule{{Name: "pattern", Param: "^[a-z]{1,3}$"}, {Name: "oneof", Param: "asc desc"}}}},
+		{in: `gospeak:"unknown"`, err: true},
+		{in: `gospeak:"min=one"`, err: true},
+		{in: `gospeak:"format=phone"`, err: true},
+		{in: `gospeak:"pattern"`, err: true},
+		{in: `gospeak:"typename"`, err: true},
+		{in: `gospeak:"name"`, err: true},
+		{in: `gospeak:"type="`, err: true},
+		{in: `gospeak:"optional,required"`, err: true},
+		{in: `gospeak:"readonly,writeonly"`, err: true},
+	}
+	for _, tc := range tt {
+		tag, ok, err := GetGospeakTag(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+		if ok != (tc.out.Value != "") {
+			t.Errorf("%s: expected ok=%v", tc.in, !ok)
+		}
+		if !cmp.Equal(tag, tc.out) {
+			t.Errorf("%s: %s", tc.in, cmp.Diff(tc.out, tag))
+		}
+	}
+}
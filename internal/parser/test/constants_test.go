@@ -0,0 +1,211 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+	"github.com/webrpc/webrpc/schema"
+)
+
+func TestConstants(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"time"
+
+		"github.com/golang-cz/gospeak/enum"
+	)
+
+	//go:webrpc json -constants -out=/dev/null
+	type TestAPI interface{
+		GetPet(ctx context.Context, ID int64) (pet *Pet, err error)
+		//gospeak:internal
+		GetStats(ctx context.Context) (count int, err error)
+	}
+
+	type Pet struct {
+		ID     int64
+		Status Status
+	}
+
+	// active   = 0
+	// pending  = 1
+	// archived = 2
+	type Status enum.Int
+
+	//gospeak:export
+	const MaxPageSize = 100
+
+	//gospeak:export
+	const (
+		DefaultLocale         = "en"
+		DefaultStatus  Status = 1
+		Ratio          float32 = 0.25
+		Debug                 = false
+		RequestTimeout        = 30 * time.Second
+		defaultRetries        = 3
+	)
+
+	const (
+		Version      = "v1.2.3" //gospeak:export
+		internalOnly = 42
+	)
+
+	const NotExported = 1
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	targets, err := gospeak.CollectInterfaces(p.Pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !targets[0].Constants {
+		t.Fatal("expected -constants target flag")
+	}
+
+	// Generators opt in to constants.
+	s, err := gospeak.ParseServices(p.Pkg, targets[0].InterfaceName, targets[0].Services, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.GetTypeByName("Constants") != nil {
+		t.Error("unexpected Constants type without -constants target flag")
+	}
+
+	s, err = gospeak.ParseServices(p.Pkg, targets[0].InterfaceName, targets[0].Services, &gospeak.Config{Constants: targets[0].Constants})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constants := s.GetTypeByName("Constants")
+	if constants == nil {
+		t.Fatal("expected Constants type")
+	}
+	if !cmp.Equal([]schema.TypeFieldMeta{{"constants": true}}, constants.TypeExtra.Meta) {
+		t.Errorf("unexpected Constants type meta: %v", constants.TypeExtra.Meta)
+	}
+
+	var got []string
+	for _, field := range constants.Fields {
+		got = append(got, fmt.Sprintf("%v %v = %#v %v", field.Name, field.Type.Expr, field.TypeExtra.Meta[0]["const"], field.TypeExtra.Meta[1:]))
+	}
+
+	want := []string{
+		`MaxPageSize int = 100 []`,
+		`DefaultLocale string = "en" []`,
+		`DefaultStatus Status = "pending" []`,
+		`Ratio float32 = 0.25 []`,
+		`Debug bool = false []`,
+		`RequestTimeout int64 = 30000000000 [map[format:duration-ns]]`,
+		`Version string = "v1.2.3" []`,
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("constants\n%s", coloredDiff(want, got))
+	}
+
+	// The constants and the enums they reference are kept by method filters.
+	filtered := (&gospeak.MethodFilter{ExcludeAnnotated: []string{"internal"}}).Apply(s)
+	var types []string
+	for _, typ := range filtered.Types {
+		types = append(types, typ.Name)
+	}
+	wantTypes := []string{"Pet", "Status", "Constants"}
+	if !cmp.Equal(wantTypes, types) {
+		t.Errorf("filtered types\n%s", coloredDiff(wantTypes, types))
+	}
+
+	filtered = (&gospeak.MethodFilter{IncludeAnnotated: []string{"internal"}}).Apply(s)
+	types = nil
+	for _, typ := range filtered.Types {
+		types = append(types, typ.Name)
+	}
+	wantTypes = []string{"Status", "Constants"}
+	if !cmp.Equal(wantTypes, types) {
+		t.Errorf("filtered types\n%s", coloredDiff(wantTypes, types))
+	}
+}
+
+func TestConstantsErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in        string
+		err       string
+		collision bool
+	}{
+		{
+			in:  "//gospeak:export\nconst DefaultStatus Status = 5",
+			err: "exporting constant DefaultStatus: value 5 is not defined by enum Status",
+		},
+		{
+			in:  "//gospeak:export\nconst maxSize = 5",
+			err: "exporting constant maxSize: constant is not exported",
+		},
+		{
+			in:  "const (\n\tmaxSize = 5 //gospeak:export\n)",
+			err: "exporting constant maxSize: constant is not exported",
+		},
+		{
+			in:  "//gospeak:export\nconst Big = 1 << 70",
+			err: "exporting constant Big",
+		},
+		{
+			in:        "//gospeak:export\nconst Max = 10\n\ntype Constants struct{ Max int }\n\ntype Req struct{ C Constants }",
+			err:       "type name Constants of exported constants collides with",
+			collision: true,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak/enum"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			GetStatus(ctx context.Context, req *Req) (status Status, err error)
+		}
+
+		type Req struct{}
+
+		// active
+		// pending
+		type Status enum.Int
+
+		%s
+		`, tc.in)
+		srcCode = strings.Replace(srcCode, "type Req struct{}", "", strings.Count(tc.in, "type Req"))
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, &gospeak.Config{Constants: true})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error %q, got %v", tc.in, tc.err, err)
+		}
+
+		// The type name is reserved only if the constants are added to the schema.
+		if tc.collision {
+			if _, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil); err != nil {
+				t.Errorf("%v: unexpected error without constants: %v", tc.in, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		GetFunc(ctx context.Context) (fn func(), err error)
+		GetStruct(ctx context.Context) (s *BrokenStruct, err error)
+		Ping(ctx context.Context) (err error)
+	}
+
+	type BrokenStruct struct {
+		Ok    int
+		Chan  chan int
+		Func  func()
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	_, err = gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var diagnostics gospeak.Diagnostics
+	if !errors.As(err, &diagnostics) {
+		t.Fatalf("expected diagnostics, got %T: %v", err, err)
+	}
+
+	type position struct {
+		file string
+		line int
+		col  int
+	}
+
+	want := []position{
+		{"proto.go", 7, 33}, // GetFunc: fn func()
+		{"proto.go", 14, 3}, // BrokenStruct.Chan
+		{"proto.go", 15, 3}, // BrokenStruct.Func
+	}
+
+	var got []position
+	for _, diagnostic := range diagnostics {
+		got = append(got, position{filepath.Base(diagnostic.Pos.Filename), diagnostic.Pos.Line, diagnostic.Pos.Column})
+	}
+
+	if !cmp.Equal(want, got, cmp.AllowUnexported(position{})) {
+		t.Errorf("%v\n%s", diagnostics, coloredDiff(want, got, cmp.AllowUnexported(position{})))
+	}
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -199,3 +200,95 @@ func TestStructFieldEnum(t *testing.T) {
 
 	}
 }
+
+func TestEnumName(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		out []string
+		err string
+	}{
+		{
+			in: `
+				//gospeak:name OrderStatus
+				// approved
+				// pending
+				type Status enum.Int
+			`,
+			out: []string{"OrderStatus:approved=0", "OrderStatus:pending=1", "TestStruct.Status:OrderStatus"},
+		},
+		{
+			in: `
+				//gospeak:name TestStruct
+				// approved
+				type Status enum.Int
+			`,
+			err: "type name TestStruct of github.com/golang-cz/gospeak/internal/parser/test.TestStruct collides with github.com/golang-cz/gospeak/internal/parser/test.Status",
+		},
+		{
+			in: `
+				//gospeak:name order-status
+				// approved
+				type Status enum.Int
+			`,
+			err: `invalid //gospeak:name "order-status": expected identifier`,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+			import (
+				"context"
+
+				"github.com/golang-cz/gospeak/enum"
+			)
+
+			%s
+
+			type TestStruct struct {
+				Status Status
+			}
+
+			//go:webrpc json -out=/dev/null
+			type TestAPI interface{
+				Test(ctx context.Context) (tst *TestStruct, err error)
+			}
+			`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("parsing: %w", err))
+		}
+
+		err = p.CollectEnums()
+		if err == nil {
+			err = parseInterface(p, "TestAPI")
+		}
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+
+		var got []string
+		for _, typ := range p.Schema.Types {
+			for _, field := range typ.Fields {
+				if typ.Kind == schema.TypeKind_Enum {
+					got = append(got, fmt.Sprintf("%v:%v=%v", typ.Name, field.Name, field.Value))
+				} else {
+					got = append(got, fmt.Sprintf("%v.%v:%v", typ.Name, field.Name, field.Type))
+				}
+			}
+		}
+		if !cmp.Equal(tc.out, got) {
+			t.Errorf("%s\n%s", tc.in, coloredDiff(tc.out, got))
+		}
+	}
+}
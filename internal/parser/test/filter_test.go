@@ -0,0 +1,123 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMethodFilter(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		GetPet(ctx context.Context, ID int64) (pet *Pet, err error)
+		ListPets(ctx context.Context) (pets []*Pet, err error)
+
+		//gospeak:internal
+		GetPetStats(ctx context.Context) (stats *Stats, err error)
+
+		DeleteUser(ctx context.Context, user *User) (err error)
+	}
+
+	type Pet struct {
+		ID   int64
+		Tags []Tag
+	}
+
+	type Tag struct {
+		Name string
+	}
+
+	type Stats struct {
+		Count int
+	}
+
+	type User struct {
+		Name string
+	}
+	`
+
+	tt := []struct {
+		filter  gospeak.MethodFilter
+		methods []string
+		types   []string
+	}{
+		{
+			filter:  gospeak.MethodFilter{},
+			methods: []string{"DeleteUser", "GetPet", "GetPetStats", "ListPets"},
+			types:   []string{"User", "Tag", "Pet", "Stats"},
+		},
+		{
+			filter:  gospeak.MethodFilter{Include: []string{"Get*", "List*"}},
+			methods: []string{"GetPet", "GetPetStats", "ListPets"},
+			types:   []string{"Tag", "Pet", "Stats"},
+		},
+		{
+			filter:  gospeak.MethodFilter{ExcludeAnnotated: []string{"internal"}},
+			methods: []string{"DeleteUser", "GetPet", "ListPets"},
+			types:   []string{"User", "Tag", "Pet"},
+		},
+		{
+			filter:  gospeak.MethodFilter{Include: []string{"Get*"}, ExcludeAnnotated: []string{"internal"}},
+			methods: []string{"GetPet"},
+			types:   []string{"Tag", "Pet"},
+		},
+		{
+			filter:  gospeak.MethodFilter{Exclude: []string{"*Pet*"}},
+			methods: []string{"DeleteUser"},
+			types:   []string{"User"},
+		},
+		{
+			filter:  gospeak.MethodFilter{IncludeAnnotated: []string{"internal"}},
+			methods: []string{"GetPetStats"},
+			types:   []string{"Stats"},
+		},
+	}
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range tt {
+		filtered := tc.filter.Apply(p.Schema)
+
+		var methods []string
+		for _, service := range filtered.Services {
+			for _, method := range service.Methods {
+				methods = append(methods, method.Name)
+				if method.Service != service {
+					t.Errorf("%+v: method %v has invalid service back-reference", tc.filter, method.Name)
+				}
+			}
+		}
+
+		var types []string
+		for _, typ := range filtered.Types {
+			types = append(types, typ.Name)
+		}
+
+		if !cmp.Equal(tc.methods, methods) {
+			t.Errorf("%+v: methods\n%s", tc.filter, coloredDiff(tc.methods, methods))
+		}
+		if !cmp.Equal(tc.types, types) {
+			t.Errorf("%+v: types\n%s", tc.filter, coloredDiff(tc.types, types))
+		}
+	}
+
+	// The original schema must be left untouched.
+	if got := len(p.Schema.Services[0].Methods); got != 4 {
+		t.Errorf("original schema was modified: expected 4 methods, got %v", got)
+	}
+}
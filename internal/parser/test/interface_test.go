@@ -0,0 +1,355 @@
+package test
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak/internal/parser"
+	"github.com/google/go-cmp/cmp"
+	"github.com/webrpc/webrpc/schema"
+)
+
+func TestInterfaceMethodAnnotations(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		// GetPet returns a pet.
+		//gospeak:deprecated use GetPetV2
+		//gospeak:auth role=admin
+		GetPet(ctx context.Context, ID int64) (err error)
+
+		//gospeak:internal
+		//gospeak:x-custom  some value
+		GetPetV2(ctx context.Context, ID int64) (err error)
+
+		ListPets(ctx context.Context) (err error)
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]schema.Annotations{
+		"GetPet": {
+			"deprecated": {AnnotationType: "deprecated", Value: "use GetPetV2"},
+			"auth":       {AnnotationType: "auth", Value: "role=admin"},
+		},
+		"GetPetV2": {
+			"internal": {AnnotationType: "internal", Value: ""},
+			"x-custom": {AnnotationType: "x-custom", Value: "some value"},
+		},
+		"ListPets": {},
+	}
+
+	got := map[string]schema.Annotations{}
+	for _, method := range p.Schema.Services[0].Methods {
+		got[method.Name] = method.Annotations
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s\n", coloredDiff(want, got))
+	}
+}
+
+func TestInterfaceMethodDuplicateAnnotation(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		//gospeak:auth role=admin
+		//gospeak:auth role=user
+		GetPet(ctx context.Context, ID int64) (err error)
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err == nil {
+		t.Errorf("expected duplicate annotation error")
+	}
+}
+
+func TestInterfaceMethodRoutes(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"time"
+
+		"github.com/golang-cz/gospeak/enum"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		//gospeak:route GET /pets/{ID}
+		GetPet(ctx context.Context, ID int64) (pet *Pet, err error)
+
+		//gospeak:route get /pets
+		ListPets(ctx context.Context, status Status, tags []string, since time.Time) (pets []*Pet, err error)
+
+		//gospeak:route POST /pets
+		CreatePet(ctx context.Context, pet *Pet) (ID int64, err error)
+
+		//gospeak:route PUT /owners/{ownerID}/pets/{ID}
+		UpdatePet(ctx context.Context, ownerID string, ID int64, pet *Pet) (err error)
+
+		//gospeak:route GET /files/{path...}
+		GetFile(ctx context.Context, path string) (content []byte, err error)
+
+		//gospeak:route GET /owners/{$}
+		ListOwners(ctx context.Context) (owners []string, err error)
+
+		DeletePet(ctx context.Context, ID int64) (err error)
+	}
+
+	// available
+	// sold
+	type Status enum.Int
+
+	type Pet struct {
+		ID   int64
+		Name string
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+	if err := p.CollectEnums(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"GetPet":     "GET /pets/{ID}",
+		"ListPets":   "GET /pets",
+		"CreatePet":  "POST /pets",
+		"UpdatePet":  "PUT /owners/{ownerID}/pets/{ID}",
+		"GetFile":    "GET /files/{path...}",
+		"ListOwners": "GET /owners/{$}",
+	}
+
+	got := map[string]string{}
+	for _, method := range p.Schema.Services[0].Methods {
+		if route, ok := method.Annotations["route"]; ok {
+			got[method.Name] = route.Value
+		}
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("routes\n%s", coloredDiff(want, got))
+	}
+}
+
+func TestInterfaceMethodRoutesErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		method string
+		err    string
+	}{
+		{"//gospeak:route /pets/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "expected <HTTP method> <path>"},
+		{"//gospeak:route FETCH /pets/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "unsupported HTTP method FETCH"},
+		{"//gospeak:route GET pets/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "path pets/{ID} must start with /"},
+		{"//gospeak:route GET /pets/{id}\nGetPet(ctx context.Context, ID int64) (err error)", "path param {id} doesn't match any method argument"},
+		{"//gospeak:route GET /pets/pet-{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "must be a whole path segment"},
+		{"//gospeak:route GET /pets/{ID}/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "duplicate path param {ID}"},
+		{"//gospeak:route GET /files/{ID...}\nGetPet(ctx context.Context, ID int64) (err error)", "path param {ID...} can't bind to argument of type int64"},
+		{"//gospeak:route GET /files/{path...}/raw\nGetFile(ctx context.Context, path string) (err error)", "must be the last path segment"},
+		{"//gospeak:route GET /pets/{$}/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "path end {$} must be the last path segment"},
+		{"//gospeak:route GET /pets/{pet}\nGetPet(ctx context.Context, pet *Pet) (err error)", "path param {pet} can't bind to"},
+		{"//gospeak:route GET /pets\nFindPets(ctx context.Context, filter *Pet) (err error)", "argument filter of type Pet can't bind to query params of GET route"},
+		{"//gospeak:route GET /pets\n//gospeak:arg q query=Tag\nFindPets(ctx context.Context, tag string, q string) (err error)", "query param Tag of argument q conflicts with argument tag"},
+		{"//gospeak:route GET /pets/{ID}\n//gospeak:arg filter query=id\nGetPet(ctx context.Context, ID int64, filter string) (err error)", "query param id of argument filter conflicts with path param {ID}"},
+		{"//gospeak:route POST /pets/{ID}\n//gospeak:arg version query=ID\nUpdatePet(ctx context.Context, ID int64, version int, pet *Pet) (err error)", "query param ID of argument version conflicts with path param {ID}"},
+		{"//gospeak:route GET /pets/{ID}\nGetPet(ctx context.Context, ID int64) (err error)\n//gospeak:route GET /pets/{petID}\nGetPetV2(ctx context.Context, petID int64) (err error)", "GetPetV2(): route GET /pets/{petID} conflicts with TestAPI.GetPet()"},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type Pet struct {
+			ID int64
+		}
+		`, tc.method)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error %q, got %v", tc.method, tc.err, err)
+		}
+	}
+}
+
+func TestInterfaceMethodArgBindings(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		//gospeak:route PUT /pets/{ID}
+		//gospeak:arg tenantID header=X-Tenant-ID
+		//gospeak:arg ifMatch header=If-Match
+		//gospeak:arg etag header=ETag
+		UpdatePet(ctx context.Context, tenantID string, ID int64, ifMatch string, pet *Pet) (etag string, err error)
+
+		//gospeak:route GET /pets
+		//gospeak:arg tenantID header=X-Tenant-ID
+		//gospeak:arg tags query=tag
+		ListPets(ctx context.Context, tenantID string, tags []string, limit int) (pets []*Pet, err error)
+	}
+
+	type Pet struct {
+		ID int64
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, method := range p.Schema.Services[0].Methods {
+		if _, ok := method.Annotations["arg"]; ok {
+			t.Errorf("%v(): unexpected arg annotation", method.Name)
+		}
+		for _, arg := range append(method.Inputs, method.Outputs...) {
+			got = append(got, fmt.Sprintf("%v.%v %v", method.Name, arg.Name, arg.TypeExtra.Meta))
+		}
+	}
+
+	want := []string{
+		"ListPets.tenantID [map[header:X-Tenant-ID]]",
+		"ListPets.tags [map[query:tag]]",
+		"ListPets.limit []",
+		"ListPets.pets []",
+		"UpdatePet.tenantID [map[header:X-Tenant-ID]]",
+		"UpdatePet.ID []",
+		"UpdatePet.ifMatch [map[header:If-Match]]",
+		"UpdatePet.pet []",
+		"UpdatePet.etag [map[header:ETag]]",
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("arguments\n%s", coloredDiff(want, got))
+	}
+}
+
+func TestInterfaceMethodArgBindingsErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		method string
+		err    string
+	}{
+		{"//gospeak:arg tenantID\nGetPet(ctx context.Context, tenantID string) (err error)", "expected <argument> header=<name> or <argument> query=<name>"},
+		{"//gospeak:arg tenantID cookie=tenant\nGetPet(ctx context.Context, tenantID string) (err error)", `unknown binding "cookie=tenant"`},
+		{"//gospeak:arg tenantID header=X:Tenant\nGetPet(ctx context.Context, tenantID string) (err error)", `invalid header name "X:Tenant"`},
+		{"//gospeak:arg tenantID header=content-type\nGetPet(ctx context.Context, tenantID string) (err error)", "header Content-Type is reserved"},
+		{"//gospeak:arg tenant header=X-Tenant-ID\nGetPet(ctx context.Context, tenantID string) (err error)", "//gospeak:arg tenant doesn't match any method argument"},
+		{"//gospeak:arg a header=X-A\n//gospeak:arg a query=a\nGetPet(ctx context.Context, a string) (err error)", "duplicate //gospeak:arg binding of argument a"},
+		{"//gospeak:arg a header=X-ID\n//gospeak:arg b header=x-id\nGetPet(ctx context.Context, a string, b string) (err error)", "header x-id is already bound to argument a"},
+		{"//gospeak:arg pet header=X-Pet\nGetPet(ctx context.Context, pet *Pet) (err error)", "argument pet of type Pet can't be bound to header X-Pet"},
+		{"//gospeak:arg ids header=X-IDs\nGetPet(ctx context.Context, ids []int64) (err error)", "argument ids of type []int64 can't be bound to header X-IDs"},
+		{"//gospeak:arg etag query=etag\nGetPet(ctx context.Context) (etag string, err error)", "output argument etag can be bound to response header only"},
+		{"//gospeak:arg ID header=X-ID\nGetPet(ctx context.Context, ID int64) (ID2 int64, err error)", ""},
+		{"//gospeak:arg ID header=X-ID\n//gospeak:route GET /pets/{ID}\nGetPet(ctx context.Context, ID int64) (err error)", "path param {ID} can't bind to argument bound by //gospeak:arg"},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type Pet struct {
+			ID int64
+		}
+		`, tc.method)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if tc.err == "" {
+			if err != nil {
+				t.Errorf("%v: unexpected error %v", tc.method, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error %q, got %v", tc.method, tc.err, err)
+		}
+	}
+}
+
+func parseInterface(p *parser.Parser, name string) error {
+	scope := p.Pkg.Types.Scope()
+
+	obj := scope.Lookup(name)
+	if obj == nil {
+		return fmt.Errorf("type %s not defined", name)
+	}
+
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return fmt.Errorf("type %s is %T, expected interface", name, obj.Type().Underlying())
+	}
+
+	if err := p.ParseInterfaceMethods(iface, name); err != nil {
+		return fmt.Errorf("failed to parse interface %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+	"github.com/webrpc/webrpc/schema"
+)
+
+func TestTypeMappings(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	//gospeak:map github.com/golang-cz/gospeak/internal/parser/test/empty.Struct=map<string,any>
+	//gospeak:map github.com/golang-cz/gospeak/internal/parser/test.Money=string
+
+	import (
+		"context"
+		"time"
+
+		"github.com/golang-cz/gospeak/internal/parser/test/empty"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Empty     empty.Struct
+		Price     Money
+		Amount    Amount
+		CreatedAt time.Time
+	}
+
+	type Money struct {
+		Units int64
+		Nanos int32
+	}
+
+	type Amount struct {
+		Value int64
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	config := &gospeak.Config{
+		TypeMappings: map[string]string{
+			"github.com/golang-cz/gospeak/internal/parser/test.Amount": "int64",
+		},
+	}
+
+	s, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range s.Types {
+		if typ.Name != "TestStruct" {
+			t.Errorf("unexpected type %v", typ.Name)
+			continue
+		}
+		for _, field := range typ.Fields {
+			got = append(got, fmt.Sprintf("%v:%v", field.Name, field.Type))
+		}
+
+		wantMeta := []schema.TypeFieldMeta{
+			{"go.field.name": "Empty"},
+			{"go.field.type": "empty.Struct"},
+			{"go.type.import": "github.com/golang-cz/gospeak/internal/parser/test/empty"},
+		}
+		if !cmp.Equal(wantMeta, typ.Fields[0].TypeExtra.Meta) {
+			t.Errorf("meta\n%s", coloredDiff(wantMeta, typ.Fields[0].TypeExtra.Meta))
+		}
+	}
+
+	want := []string{
+		"Empty:map<string,any>",
+		"Price:string",
+		"Amount:int64",
+		"CreatedAt:timestamp",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("fields\n%s", coloredDiff(want, got))
+	}
+}
+
+func TestTypeMappingErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		err string
+	}{
+		{in: `//gospeak:map Money`, err: "invalid //gospeak:map Money: expected <Go type>=<webrpc type>"},
+		{in: `//gospeak:map Money=string`, err: "expected fully qualified Go type"},
+		{in: `//gospeak:map github.com/golang-cz/gospeak/internal/parser/test.Money=decimal`, err: `failed to map github.com/golang-cz/gospeak/internal/parser/test.Money to webrpc type "decimal"`},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		%s
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			Test(ctx context.Context) (money *Money, err error)
+		}
+
+		type Money struct {
+			Units int64
+		}
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		_, err = gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/webrpc/webrpc/schema"
+)
+
+func TestPagination(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+
+		"github.com/golang-cz/gospeak"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		ListPets(ctx context.Context, cursor gospeak.Cursor, limit int) (page *gospeak.Page[*Pet], err error)
+		SearchPets(ctx context.Context, query string, next gospeak.Cursor) (results gospeak.Page[Pet], err error)
+		ListNames(ctx context.Context, cursor gospeak.Cursor) (names *gospeak.Page[string], err error)
+		GetPet(ctx context.Context, ID int64) (result Result[Pet], err error)
+	}
+
+	type Pet struct {
+		ID int64
+	}
+
+	type Result[T any] struct {
+		Data T
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for _, typ := range p.Schema.Types {
+		var fields []string
+		for _, field := range typ.Fields {
+			fields = append(fields, fmt.Sprintf("%v %v", field.Name, field.Type.Expr))
+		}
+		types = append(types, fmt.Sprintf("%v{%v} %v", typ.Name, strings.Join(fields, ", "), typ.TypeExtra.Meta))
+	}
+
+	wantTypes := []string{
+		"Pet{ID int64} []",
+		"PetResult{Data Pet} []",
+		"StringPage{items []string, nextCursor string} [map[pagination:cursor]]",
+		"PetPage{items []Pet, nextCursor string} [map[pagination:cursor]]",
+	}
+	if !cmp.Equal(wantTypes, types) {
+		t.Errorf("types\n%s", coloredDiff(wantTypes, types))
+	}
+
+	annotations := map[string]string{}
+	outputs := map[string]string{}
+	for _, method := range p.Schema.Services[0].Methods {
+		if annotation, ok := method.Annotations["paginated"]; ok {
+			annotations[method.Name] = annotation.Value
+		}
+		outputs[method.Name] = method.Outputs[0].Type.Expr
+	}
+
+	wantAnnotations := map[string]string{
+		"ListPets":   "cursor=cursor page=page",
+		"SearchPets": "cursor=next page=results",
+		"ListNames":  "cursor=cursor page=names",
+	}
+	if !cmp.Equal(wantAnnotations, annotations) {
+		t.Errorf("annotations\n%s", coloredDiff(wantAnnotations, annotations))
+	}
+
+	wantOutputs := map[string]string{
+		"ListPets":   "PetPage",
+		"SearchPets": "PetPage",
+		"ListNames":  "StringPage",
+		"GetPet":     "PetResult",
+	}
+	if !cmp.Equal(wantOutputs, outputs) {
+		t.Errorf("outputs\n%s", coloredDiff(wantOutputs, outputs))
+	}
+
+	// Both gospeak.Page[*Pet] and gospeak.Page[Pet] reference the same schema type.
+	var pages []*schema.Type
+	for _, method := range p.Schema.Services[0].Methods {
+		if method.Name == "ListPets" || method.Name == "SearchPets" {
+			pages = append(pages, method.Outputs[0].Type.Struct.Type)
+		}
+	}
+	if len(pages) != 2 || pages[0] != pages[1] {
+		t.Errorf("expected ListPets and SearchPets to return the same PetPage type")
+	}
+}
+
+func TestPaginationErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		method string
+		err    string
+	}{
+		{
+			"ListPets(ctx context.Context, limit int) (page *gospeak.Page[*Pet], err error)",
+			"ListPets(): paginated method must accept gospeak.Cursor argument",
+		},
+		{
+			"ListPets(ctx context.Context, a gospeak.Cursor, b gospeak.Cursor) (page *gospeak.Page[*Pet], err error)",
+			"ListPets(): paginated method must accept a single gospeak.Cursor argument",
+		},
+		{
+			"ListPets(ctx context.Context, cursor gospeak.Cursor) (a *gospeak.Page[*Pet], b *gospeak.Page[*Pet], err error)",
+			"ListPets(): paginated method must return a single gospeak.Page",
+		},
+		{
+			"//gospeak:paginated\nListPets(ctx context.Context, cursor gospeak.Cursor) (page *gospeak.Page[*Pet], err error)",
+			"//gospeak:paginated annotation is added automatically",
+		},
+		{
+			"A(ctx context.Context) (a *Result[[]Pet], err error)\nB(ctx context.Context, since gospeak.Cursor) (b *Result[PetList], err error)",
+			"type name PetListResult of github.com/golang-cz/gospeak/internal/parser/test.Result[github.com/golang-cz/gospeak/internal/parser/test.PetList] collides with github.com/golang-cz/gospeak/internal/parser/test.Result[[]github.com/golang-cz/gospeak/internal/parser/test.Pet]",
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type Pet struct {
+			ID int64
+		}
+
+		type PetList []Pet
+
+		type Result[T any] struct {
+			Data T
+		}
+		`, tc.method)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error %q, got %v", tc.method, tc.err, err)
+		}
+	}
+}
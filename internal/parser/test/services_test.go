@@ -0,0 +1,79 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMultipleServices(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -services=UserAPI,BillingAPI -out=/dev/null
+	type UserAPI interface{
+		GetUser(ctx context.Context, ID int64) (user *User, err error)
+	}
+
+	type BillingAPI interface{
+		GetInvoice(ctx context.Context, ID int64) (invoice *Invoice, err error)
+	}
+
+	type User struct {
+		ID int64
+	}
+
+	type Invoice struct {
+		ID   int64
+		User *User
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	targets, err := gospeak.CollectInterfaces(p.Pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %v", len(targets))
+	}
+
+	s, err := gospeak.ParseServices(p.Pkg, targets[0].InterfaceName, targets[0].Services, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.SchemaName != "UserAPI" {
+		t.Errorf("expected schema name UserAPI, got %v", s.SchemaName)
+	}
+
+	var services []string
+	for _, service := range s.Services {
+		services = append(services, service.Name)
+	}
+
+	var types []string
+	for _, typ := range s.Types {
+		types = append(types, typ.Name)
+	}
+
+	wantServices := []string{"UserAPI", "BillingAPI"}
+	if !cmp.Equal(wantServices, services) {
+		t.Errorf("services\n%s", coloredDiff(wantServices, services))
+	}
+
+	// User type is shared across both services.
+	wantTypes := []string{"User", "Invoice"}
+	if !cmp.Equal(wantTypes, types) {
+		t.Errorf("types\n%s", coloredDiff(wantTypes, types))
+	}
+}
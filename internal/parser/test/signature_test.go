@@ -0,0 +1,139 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak/internal/parser"
+)
+
+func TestMethodSignature(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		err []string // expected diagnostics, in order
+	}{
+		{in: `Test(ctx context.Context) error`},
+		{in: `Test(ctx context.Context) (err error)`},
+		{in: `Test(context.Context, int64, string) (*Pet, error)`},
+		{in: `Test(ctx context.Context, ID int64) (pet *Pet, err error)`},
+		{in: `Test(ctx Ctx) error`}, // type alias
+		{
+			in:  `Test() error`,
+			err: []string{"first argument must be context.Context: no arguments defined (fix: add ctx context.Context as the first argument)"},
+		},
+		{
+			in:  `Test(ctx Context) error`, // not context.Context
+			err: []string{"first argument must be context.Context: found github.com/golang-cz/gospeak/internal/parser/test.Context (fix: replace the first argument ctx Context with ctx context.Context)"},
+		},
+		{
+			in:  `Test(ctx context.Context)`,
+			err: []string{"last return value must be error: no return values defined"},
+		},
+		{
+			in:  `Test(ctx context.Context) (pet *Pet)`,
+			err: []string{"last return value must be error: found *github.com/golang-cz/gospeak/internal/parser/test.Pet"},
+		},
+		{
+			in:  `Test(ctx context.Context) (err Error)`, // not the predeclared error
+			err: []string{"last return value must be error"},
+		},
+		{
+			in:  `Test(ctx context.Context, IDs ...int64) error`,
+			err: []string{"variadic argument IDs ...int64 is not supported (fix: use a slice instead: IDs []int64)"},
+		},
+		{
+			in:  `Test(ctx context.Context, _ int64) error`,
+			err: []string{"blank argument name _ is not supported (fix: name the argument, ie. int64Req int64)"},
+		},
+		{
+			in:  `Test(ctx context.Context) (_ *Pet, err error)`,
+			err: []string{"blank return value name _ is not supported"},
+		},
+		{
+			in:  `Test(context.Context, *Pet, *Pet) error`,
+			err: []string{`duplicate argument name "petReq" generated from unnamed arguments`},
+		},
+		{
+			in:  `Test(ctx context.Context, id int64, ID int64) error`,
+			err: []string{`duplicate argument name "ID"`},
+		},
+		{
+			in:  `Test(ctx context.Context, ctx2 context.Context) error`,
+			err: []string{"context.Context is allowed only as the first argument"},
+		},
+		{
+			in:  `Test(ctx context.Context) (err1 error, err2 error)`,
+			err: []string{"error is allowed only as the last return value"},
+		},
+		{
+			in: `Test(ID int64, _ string, IDs ...int64) (pet *Pet)`,
+			err: []string{
+				"first argument must be context.Context: found int64 (fix: replace the first argument ID int64 with ctx context.Context)",
+				"blank argument name _ is not supported",
+				"variadic argument IDs ...int64 is not supported",
+				"last return value must be error",
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type Pet struct {
+			ID int64
+		}
+
+		type Context interface{}
+
+		type Ctx = context.Context
+
+		type Error interface {
+			Error() string
+		}
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if len(tc.err) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tc.in, err)
+			}
+			continue
+		}
+
+		var diagnostics parser.Diagnostics
+		if !errors.As(err, &diagnostics) {
+			t.Errorf("%s: expected diagnostics, got %v", tc.in, err)
+			continue
+		}
+
+		if len(diagnostics) != len(tc.err) {
+			t.Errorf("%s: expected %v diagnostics, got %v:\n%v", tc.in, len(tc.err), len(diagnostics), diagnostics)
+			continue
+		}
+
+		for i, diagnostic := range diagnostics {
+			if !diagnostic.Pos.IsValid() {
+				t.Errorf("%s: diagnostic %q has no position", tc.in, diagnostic.Msg)
+			}
+			if !strings.Contains(diagnostic.Error(), tc.err[i]) {
+				t.Errorf("%s: expected %q, got %q", tc.in, tc.err[i], diagnostic.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStreamOutput(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in     string
+		stream bool
+		output string // name:type of the single output
+		err    string
+	}{
+		{in: `Test(ctx context.Context) (pet *Pet, err error)`, output: "pet:Pet"},
+		{in: `Test(ctx context.Context) (<-chan *Pet, error)`, stream: true, output: "pet:Pet"},
+		{in: `Test(ctx context.Context) (pets <-chan *Pet, err error)`, stream: true, output: "pets:Pet"},
+		{in: `Test(ctx context.Context) (chan Pet, error)`, stream: true, output: "pet:Pet"},
+		{in: `Test(ctx context.Context) (<-chan int64, error)`, stream: true, output: "int64:int64"},
+		{in: `Test(ctx context.Context) (iter.Seq[*Pet], error)`, stream: true, output: "pet:Pet"},
+		{in: `Test(ctx context.Context) (iter.Seq2[*Pet, error], error)`, stream: true, output: "pet:Pet"},
+		{in: `Test(ctx context.Context) (chan<- *Pet, error)`, err: "send-only channel"},
+		{in: `Test(ctx context.Context) (iter.Seq2[*Pet, int], error)`, err: "must yield error as the second value"},
+		{in: `Test(ctx context.Context) (<-chan *Pet, int, error)`, err: "must return exactly (stream, error)"},
+		{in: `Test(ctx context.Context) (a <-chan *Pet, b <-chan *Pet, err error)`, err: "must return a single stream"},
+		{in: `Test(ctx context.Context, ch <-chan *Pet) error`, err: "unsupported argument type *types.Chan"},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+			"iter"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type Pet struct {
+			ID int64
+		}
+
+		var _ iter.Seq[int]
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+
+		method := p.Schema.Services[0].Methods[0]
+		if method.StreamOutput != tc.stream {
+			t.Errorf("%s: expected StreamOutput=%v", tc.in, tc.stream)
+		}
+
+		if len(method.Outputs) != 1 {
+			t.Errorf("%s: expected 1 output, got %v", tc.in, len(method.Outputs))
+			continue
+		}
+
+		if output := method.Outputs[0].Name + ":" + method.Outputs[0].Type.String(); output != tc.output {
+			t.Errorf("%s: expected output %v, got %v", tc.in, tc.output, output)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/golang-cz/gospeak"
 	"github.com/google/go-cmp/cmp"
 	"github.com/webrpc/webrpc/schema"
 )
@@ -225,3 +228,1016 @@ func TestStructSliceField(t *testing.T) {
 		}
 	}
 }
+
+func TestStructFieldGospeakTag(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		out *schema.TypeField
+		err string
+	}{
+		{
+			in:  "ID int64 `gospeak:\"-\"`", // hidden from the API
+			out: nil,
+		},
+		{
+			in: "ID int64 `json:\"id\" gospeak:\"name=petId\"`",
+			out: &schema.TypeField{
+				Name: "petId",
+				Type: &schema.VarType{Expr: "int64", Type: schema.T_Int64},
+				TypeExtra: schema.TypeExtra{
+					Meta: []schema.TypeFieldMeta{
+						{"go.field.name": "ID"},
+						{"go.field.type": "int64"},
+						{"go.tag.json": "id"},
+						{"json": "id"},
+						{"go.tag.gospeak": "name=petId"},
+					},
+				},
+			},
+		},
+		{
+			in: "ID int64 `gospeak:\"type=string,optional,readonly,deprecated\"`",
+			out: &schema.TypeField{
+				Name: "ID",
+				Type: &schema.VarType{Expr: "string", Type: schema.T_String},
+				TypeExtra: schema.TypeExtra{
+					Optional: true,
+					Meta: []schema.TypeFieldMeta{
+						{"go.field.name": "ID"},
+						{"go.field.type": "int64"},
+						{"readonly": true},
+						{"deprecated": true},
+						{"go.tag.gospeak": "type=string,optional,readonly,deprecated"},
+					},
+				},
+			},
+		},
+		{
+			in: "ID *int64 `gospeak:\"required,writeonly\"`",
+			out: &schema.TypeField{
+				Name: "ID",
+				Type: &schema.VarType{Expr: "int64", Type: schema.T_Int64},
+				TypeExtra: schema.TypeExtra{
+					Meta: []schema.TypeFieldMeta{
+						{"go.field.name": "ID"},
+						{"go.field.type": "*int64"},
+						{"writeonly": true},
+						{"go.tag.gospeak": "required,writeonly"},
+					},
+				},
+			},
+		},
+		{
+			in:  "ID int64 `gospeak:\"type=bigint\"`",
+			err: "invalid gospeak tag type=bigint",
+		},
+		{
+			in:  "ID int64 `gospeak:\"whatever\"`",
+			err: `unknown gospeak tag option "whatever"`,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := genCodeWithStructField("TestStruct", tc.in)
+
+		if tc.err != "" {
+			p, err := testParser(srcCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = parseStruct(p, "TestStruct")
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+			}
+			continue
+		}
+
+		var fields []*schema.TypeField
+		if tc.out != nil {
+			fields = []*schema.TypeField{tc.out}
+		}
+
+		want := &schema.Type{
+			Kind:   "struct",
+			Name:   "TestStruct",
+			Fields: fields,
+		}
+
+		got := parseTestStructCode(t, srcCode)
+
+		if !cmp.Equal(want, got) {
+			t.Errorf("%s\n%s", tc.in, coloredDiff(want, got))
+		}
+	}
+}
+
+func TestStructFieldTimeTypes(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"time"
+
+		"github.com/golang-cz/gospeak"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Timeout   time.Duration
+		Interval  gospeak.Duration
+		Birthday  gospeak.Date
+		OpensAt   *gospeak.TimeOfDay
+		CreatedAt time.Time
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		for _, field := range typ.Fields {
+			format := ""
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["format"]; ok {
+					format = value.(string)
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v:%v", field.Name, field.Type, format))
+		}
+	}
+
+	want := []string{
+		"Timeout:int64:duration-ns",
+		"Interval:string:duration",
+		"Birthday:string:date",
+		"OpensAt:string:time",
+		"CreatedAt:timestamp:",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldOptionalNullable(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+
+		"github.com/golang-cz/gospeak"
+		"github.com/golang-cz/gospeak/internal/parser/test/empty"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Name     gospeak.Optional[string]                 ` + "`json:\"name,omitzero\"`" + `
+		Email    gospeak.Optional[gospeak.Nullable[string]] ` + "`json:\"email,omitzero\"`" + `
+		Age      gospeak.Nullable[int64]                  ` + "`json:\"age\"`" + `
+		Tags     []gospeak.Nullable[string]
+		Empty    gospeak.Optional[empty.Struct]           ` + "`json:\",omitzero\"`" + `
+		Interval gospeak.Nullable[gospeak.Duration]
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			var goType, nullable string
+			var imports []string
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["go.field.type"]; ok {
+					goType = value.(string)
+				}
+				if value, ok := meta["go.type.import"]; ok {
+					imports = append(imports, value.(string))
+				}
+				if _, ok := meta["nullable"]; ok {
+					nullable = "nullable"
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v:optional=%v:%v:%v:%v", field.Name, field.Type, field.TypeExtra.Optional, nullable, goType, imports))
+		}
+	}
+
+	want := []string{
+		"name:string:optional=true::gospeak.Optional[string]:[github.com/golang-cz/gospeak]",
+		"email:string:optional=true:nullable:gospeak.Optional[gospeak.Nullable[string]]:[github.com/golang-cz/gospeak]",
+		"age:int64:optional=false:nullable:gospeak.Nullable[int64]:[github.com/golang-cz/gospeak]",
+		"Tags:[]string:optional=false::[]gospeak.Nullable[string]:[github.com/golang-cz/gospeak]",
+		"Empty:emptyStruct:optional=true::gospeak.Optional[empty.Struct]:[github.com/golang-cz/gospeak github.com/golang-cz/gospeak/internal/parser/test/empty]",
+		"Interval:string:optional=false:nullable:gospeak.Nullable[gospeak.Duration]:[github.com/golang-cz/gospeak]",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldOptionalWithoutOmitzero(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		err string
+	}{
+		{
+			in:  "Name gospeak.Optional[string]",
+			err: "optional field Name must be omitted from JSON if absent (fix: add omitzero option, ie. `json:\"Name,omitzero\"`)",
+		},
+		{
+			in:  "Name gospeak.Optional[gospeak.Nullable[string]] `json:\"name,omitempty\"`",
+			err: "optional field Name must be omitted from JSON if absent (fix: add omitzero option, ie. `json:\"name,omitzero\"`)",
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			Test(ctx context.Context) (tst *TestStruct, err error)
+		}
+
+		type TestStruct struct {
+			%s
+		}
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = parseStruct(p, "TestStruct")
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestStructFieldOmitzero(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"time"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		ID        int64     ` + "`json:\"id\"`" + `
+		Count     int64     ` + "`json:\"count,omitzero\"`" + `
+		CreatedAt time.Time ` + "`json:\"createdAt,omitzero\"`" + `
+		Address   Address   ` + "`json:\"address,omitzero\"`" + `
+	}
+
+	type Address struct {
+		City string
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			var goType, omitzero string
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["go.field.type"]; ok {
+					goType = value.(string)
+				}
+				if value, ok := meta["go.field.omitzero"]; ok {
+					omitzero = value.(string)
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v:optional=%v:%v:%v", field.Name, field.Type, field.TypeExtra.Optional, goType, omitzero))
+		}
+	}
+
+	want := []string{
+		"id:int64:optional=false:int64:",
+		"count:int64:optional=true:int64:zero",
+		"createdAt:timestamp:optional=true:time.Time:IsZero",
+		"address:Address:optional=true:Address:zero",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldEmbedded(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		ID    int64
+		base  // unexported, but its exported fields are promoted
+		*Audit
+		A
+		B
+		Code
+		Title string ` + "`json:\"title\"`" + `
+		Internal ` + "`gospeak:\"-\"`" + `
+		Other
+	}
+
+	type base struct {
+		Name      string
+		CreatedAt int64
+		hidden    string
+	}
+
+	type Audit struct {
+		ID        string // dominated by shallower TestStruct.ID
+		UpdatedBy string // optional, since *Audit can be nil
+	}
+
+	type A struct {
+		Dup    string // ambiguous with B.Dup
+		Memo   string ` + "`json:\"Note\"`" + ` // dominates untagged B.Note
+		Shared
+	}
+
+	type B struct {
+		Dup  string
+		Note string
+		Shared
+	}
+
+	type Shared struct {
+		S string // ambiguous, since Shared is embedded twice at the same depth
+	}
+
+	type Code string // embedded non-struct type is a named field
+
+	type Internal struct {
+		Token string // hidden, promoted from the hidden embedded struct
+		Kind  string // ambiguous with Other.Kind, although hidden
+	}
+
+	type Other struct {
+		Kind string
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			var goName string
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["go.field.name"]; ok {
+					goName = value.(string)
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v:%v:optional=%v", field.Name, goName, field.Type, field.TypeExtra.Optional))
+		}
+	}
+
+	want := []string{
+		"ID:ID:int64:optional=false",
+		"Name:Name:string:optional=false",
+		"CreatedAt:CreatedAt:int64:optional=false",
+		"UpdatedBy:UpdatedBy:string:optional=true",
+		"Note:Memo:string:optional=false",
+		"Code:Code:string:optional=false",
+		"title:Title:string:optional=false",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldBytes(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"encoding/json"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Data     []byte
+		Blob     Blob
+		Optional *[]byte
+		Chunks   [][]byte
+		Raw      json.RawMessage
+		Raws     []json.RawMessage
+	}
+
+	type Blob []byte
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			format := ""
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["format"]; ok {
+					format = value.(string)
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v:%v", field.Name, field.Type, format))
+		}
+	}
+
+	want := []string{
+		"Data:string:byte",
+		"Blob:string:byte",
+		"Optional:string:byte",
+		"Chunks:[]string:",
+		"Raw:any:",
+		"Raws:[]any:",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldMapKeys(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in   string
+		out  string
+		enum string
+		err  string
+	}{
+		{in: "map[string]int64", out: "map<string,int64>"},
+		{in: "map[Name]int64", out: "map<string,int64>"},
+		{in: "map[int]string", out: "map<int,string>"},
+		{in: "map[uint64]string", out: "map<uint64,string>"},
+		{in: "map[uintptr]string", out: "map<uint64,string>"},
+		{in: "map[uuid.UUID]string", out: "map<string,string>"},
+		{in: "map[time.Time]string", out: "map<string,string>"},
+		{in: "map[Status]int64", out: "map<Status,int64>", enum: "Status"},
+		{in: "map[string]map[Status]bool", out: "map<string,map<Status,bool>>"},
+		{in: "map[float64]string", err: "invalid map key type float64"},
+		{in: "map[bool]string", err: "invalid map key type bool"},
+		{in: "map[Key]string", err: "invalid map key type Key"},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+			"time"
+
+			"github.com/golang-cz/gospeak/enum"
+			"github.com/golang-cz/gospeak/internal/parser/test/uuid"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			Test(ctx context.Context) (tst *TestStruct, err error)
+		}
+
+		type TestStruct struct {
+			Field %s
+		}
+
+		type Name string
+
+		type Key struct {
+			ID int64
+		}
+
+		// active
+		// inactive
+		type Status enum.Int
+
+		var _ time.Time
+		var _ uuid.UUID
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := p.CollectEnums(); err != nil {
+			t.Fatal(err)
+		}
+
+		err = parseStruct(p, "TestStruct")
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+
+		for _, typ := range p.Schema.Types {
+			if typ.Name != "TestStruct" {
+				continue
+			}
+			field := typ.Fields[0]
+			if got := field.Type.String(); got != tc.out {
+				t.Errorf("%s: expected %v, got %v", tc.in, tc.out, got)
+			}
+
+			enum := ""
+			for _, meta := range field.TypeExtra.Meta {
+				if value, ok := meta["map.key.enum"]; ok {
+					enum = value.(string)
+				}
+			}
+			if enum != tc.enum {
+				t.Errorf("%s: expected map.key.enum meta %q, got %q", tc.in, tc.enum, enum)
+			}
+		}
+	}
+}
+
+func TestStructFieldAnonymous(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		out []string
+		err string
+	}{
+		{
+			in: `
+			type Pet struct {
+				Meta struct {
+					Name  string
+					Owner *struct {
+						ID int64
+					}
+				}
+				Tags  []struct{ Name string }
+				Attrs map[string]struct{ Value string }
+			}
+
+			type Order struct {
+				Meta struct {
+					ID int64
+				}
+				Note *struct {
+					Text string
+				} ` + "`gospeak:\"typename=OrderComment\"`" + `
+			}`,
+			out: []string{
+				"CreatePetReq{Name:string}",
+				"CreatePetResp{ID:int64}",
+				"Pet{Meta:PetMeta,Tags:[]PetTags,Attrs:map<string,PetAttrs>}",
+				"PetMeta{Name:string,Owner:PetMetaOwner}",
+				"PetMetaOwner{ID:int64}",
+				"PetTags{Name:string}",
+				"PetAttrs{Value:string}",
+				"Order{Meta:OrderMeta,Note:OrderComment}",
+				"OrderMeta{ID:int64}",
+				"OrderComment{Text:string}",
+			},
+		},
+		{
+			in: `
+			type Pet struct {
+				Meta struct{ Name string } ` + "`gospeak:\"typename=Meta\"`" + `
+			}
+
+			type Order struct {
+				Meta struct{ ID int64 } ` + "`gospeak:\"typename=Meta\"`" + `
+			}`,
+			err: "type name Meta of anonymous struct{Name string} collides with struct{ID int64}",
+		},
+		{
+			in: `
+			type Pet struct {
+				Meta struct{ Name string } ` + "`gospeak:\"typename=pet-meta\"`" + `
+			}
+
+			type Order struct {}`,
+			err: "invalid gospeak tag typename=pet-meta: expected identifier",
+		},
+		{
+			in: `
+			type Pet struct {
+				Name string ` + "`gospeak:\"typename=PetName\"`" + `
+			}
+
+			type Order struct {}`,
+			err: "gospeak tag typename=PetName is allowed only on anonymous struct fields",
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			GetPet(ctx context.Context) (pet *Pet, err error)
+			GetOrder(ctx context.Context) (order *Order, err error)
+			CreatePet(ctx context.Context, req struct{ Name string }) (resp *struct{ ID int64 }, err error)
+		}
+
+		%s
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		s, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error %q, got %v", tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, typ := range s.Types {
+			var fields []string
+			for _, field := range typ.Fields {
+				fields = append(fields, fmt.Sprintf("%v:%v", field.Name, field.Type))
+			}
+			got = append(got, fmt.Sprintf("%v{%v}", typ.Name, strings.Join(fields, ",")))
+		}
+		if !cmp.Equal(tc.out, got) {
+			t.Errorf("types\n%s", coloredDiff(tc.out, got))
+		}
+	}
+}
+
+func TestStructFieldValidation(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Name  *string           ` + "`json:\"name,omitempty\" validate:\"required,min=1,max=64\"`" + `
+		Email string            ` + "`validate:\"omitempty,email\"`" + `
+		Age   int               ` + "`validate:\"gte=0,lt=150\"`" + `
+		Score float64           ` + "`gospeak:\"min=0.5,max=10\"`" + `
+		Code  string            ` + "`gospeak:\"pattern=^[A-Z]{20x2C3}$\"`" + `
+		Sort  string            ` + "`validate:\"oneof=asc desc\"`" + `
+		Tags  []string          ` + "`validate:\"max=10,dive,min=1\"`" + `
+		Names []string          ` + "`validate:\"dive,min=1\" gospeak:\"min=1\"`" + `
+		Attrs map[string]string ` + "`validate:\"gt=0\"`" + `
+		Nick  string            ` + "`validate:\"alphanum\"`" + `
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			var constraints []string
+			for _, meta := range field.TypeExtra.Meta {
+				for key, value := range meta {
+					if strings.HasPrefix(key, "go.") {
+						continue
+					}
+					constraints = append(constraints, fmt.Sprintf("%v=%v", key, value))
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:optional=%v:%v", field.Name, field.TypeExtra.Optional, strings.Join(constraints, ",")))
+		}
+	}
+
+	want := []string{
+		"name:optional=false:minLength=1,maxLength=64",
+		"Email:optional=false:format=email",
+		"Age:optional=false:minimum=0,exclusiveMaximum=150",
+		"Score:optional=false:minimum=0.5,maximum=10",
+		"Code:optional=false:pattern=^[A-Z]{2,3}$",
+		"Sort:optional=false:enum=[asc desc]",
+		"Tags:optional=false:maxItems=10",
+		"Names:optional=false:minItems=1",
+		"Attrs:optional=false:minProperties=1",
+		"Nick:optional=false:pattern=^[a-zA-Z0-9]+$",
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldExamples(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import (
+		"context"
+		"time"
+
+		"github.com/golang-cz/gospeak"
+		"github.com/golang-cz/gospeak/enum"
+	)
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	// active
+	// closed
+	type Status enum.Int
+
+	type Pet struct {
+		Name string ` + "`json:\"name\"`" + `
+		Age  int    ` + "`json:\"age\" gospeak:\"name=years\"`" + `
+	}
+
+	type TestStruct struct {
+		Limit   int                      ` + "`json:\"limit\" default:\"10\" example:\"25\"`" + `
+		Email   string                   ` + "`json:\"email\" example:\"john@example.com\"`" + `
+		Enabled bool                     ` + "`json:\"enabled\" default:\"true\"`" + `
+		Ratio   float32                  ` + "`json:\"ratio\" example:\"0.5\"`" + `
+		Status  Status                   ` + "`json:\"status\" default:\"active\"`" + `
+		Created time.Time                ` + "`json:\"created\" example:\"2024-01-02T15:04:05Z\"`" + `
+		Day     gospeak.Date             ` + "`json:\"day\" example:\"2024-01-02\"`" + `
+		Tags    []string                 ` + "`json:\"tags\" example:\"[\\\"go\\\", \\\"rpc\\\"]\"`" + `
+		Note    gospeak.Nullable[string] ` + "`json:\"note\" default:\"null\"`" + `
+		//gospeak:example {"name": "Fido", "age": 3}
+		Pet     *Pet                     ` + "`json:\"pet\"`" + `
+		Counts  map[Status]int           // gospeak:ignored line comment
+		Ids     map[Status]uint8         //gospeak:example {"closed": 255}
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.CollectEnums(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			for _, meta := range field.TypeExtra.Meta {
+				for key, value := range meta {
+					if key == "default" || key == "example" {
+						got = append(got, fmt.Sprintf("%v:%v=%#v", field.Name, key, value))
+					}
+				}
+			}
+		}
+	}
+
+	want := []string{
+		`limit:default=10`,
+		`limit:example=25`,
+		`email:example="john@example.com"`,
+		`enabled:default=true`,
+		`ratio:example=0.5`,
+		`status:default="active"`,
+		`created:example="2024-01-02T15:04:05Z"`,
+		`day:example="2024-01-02"`,
+		`tags:example=[]interface {}{"go", "rpc"}`,
+		`note:default=<nil>`,
+		`pet:example=map[string]interface {}{"age":"3", "name":"Fido"}`,
+		`Ids:example=map[string]interface {}{"closed":"255"}`,
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected examples:\n%s", coloredDiff(want, got))
+	}
+
+	invalid := []struct {
+		field string
+		err   string
+	}{
+		{"BadLimit int `default:\"ten\"`", `invalid default value "ten": expected int`},
+		{"BadSmall int8 `example:\"300\"`", `invalid example value "300": expected int8`},
+		{"BadFlag bool `default:\"yes\"`", `invalid default value "yes": expected bool`},
+		{"BadStatus Status `default:\"deleted\"`", `invalid default value "deleted": expected Status enum value`},
+		{"BadDay gospeak.Date `example:\"2024-13-01\"`", `invalid example value "2024-13-01": expected date format`},
+		{"BadCreated time.Time `example:\"yesterday\"`", `expected RFC 3339 timestamp`},
+		{"BadTags []int `example:\"[1, \\\"two\\\"]\"`", `invalid example value "[1, \"two\"]": [1]: expected int, got string`},
+		{"BadPet Pet `example:\"{\\\"color\\\": 1}\"`", `unknown field "color" of Pet`},
+		{"BadPetName Pet `example:\"{\\\"years\\\": 1}\"`", `unknown field "years" of Pet`},
+		{"BadName string `default:\"null\"` //gospeak:default john", `default value given by both struct tag and //gospeak:default directive`},
+	}
+
+	for _, tc := range invalid {
+		src := strings.Replace(srcCode, "type TestStruct struct {", "type TestStruct struct {\n\t\t"+tc.field+"\n", 1)
+
+		p, err := testParser(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.CollectEnums(); err != nil {
+			t.Fatal(err)
+		}
+
+		err = parseStruct(p, "TestStruct")
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error %q, got %v", tc.field, tc.err, err)
+		}
+	}
+}
+
+func TestStructFieldSensitive(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		Test(ctx context.Context) (tst *TestStruct, err error)
+	}
+
+	type TestStruct struct {
+		Username string ` + "`json:\"username\"`" + `
+		Password string ` + "`json:\"password\" gospeak:\"sensitive\"`" + `
+		Token    string ` + "`json:\"token\" gospeak:\"sensitive,readonly\"`" + `
+		PIN      int    ` + "`json:\"pin\" gospeak:\"sensitive\"`" + `
+		Secret   string ` + "`json:\"secret\" validate:\"uuid\" gospeak:\"sensitive\"`" + `
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseStruct(p, "TestStruct"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, typ := range p.Schema.Types {
+		if typ.Name != "TestStruct" {
+			continue
+		}
+		for _, field := range typ.Fields {
+			var meta []string
+			for _, m := range field.TypeExtra.Meta {
+				for key, value := range m {
+					if !strings.HasPrefix(key, "go.") {
+						meta = append(meta, fmt.Sprintf("%v=%v", key, value))
+					}
+				}
+			}
+			got = append(got, fmt.Sprintf("%v:%v", field.Name, strings.Join(meta, ",")))
+		}
+	}
+
+	want := []string{
+		"username:",
+		"password:sensitive=true,format=password,writeonly=true",
+		"token:readonly=true,sensitive=true,format=password",
+		"pin:sensitive=true,writeonly=true",
+		"secret:format=uuid,sensitive=true,writeonly=true",
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected meta:\n%s", coloredDiff(want, got))
+	}
+}
+
+func TestStructFieldSensitiveComment(t *testing.T) {
+	t.Parallel()
+
+	for _, in := range []string{
+		"//gospeak:sensitive\n\t\tPassword string",
+		"Password string //gospeak:sensitive",
+	} {
+		p, err := testParser(genCodeWithStructField("TestStruct", in))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "struct field Password: //gospeak:sensitive comment is not supported, since gospeak.Redact() can't see it (fix: use `gospeak:\"sensitive\"` struct tag)"
+		err = parseStruct(p, "TestStruct")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error %q, got %v", in, want, err)
+		}
+	}
+}
@@ -92,6 +92,8 @@ func testParser(srcCode string) (*parser.Parser, error) {
 	pkg1 := filepath.Join(wd, "proto.go")
 	pkg2 := filepath.Join(wd, "uuid/uuid.go")
 	pkg3 := filepath.Join(wd, "empty/empty.go")
+	pkg4 := filepath.Join(wd, "models/models.go")
+	pkg5 := filepath.Join(wd, "v2/models/models.go")
 
 	cfg := &packages.Config{
 		Dir:  wd,
@@ -118,10 +120,37 @@ func testParser(srcCode string) (*parser.Parser, error) {
 
 				type Struct struct{}
 			`),
+			pkg4: []byte(`
+				package models
+
+				type User struct {
+					ID int64
+				}
+
+				// Shape is implemented by the shapes of this package only.
+				type Shape interface {
+					isShape()
+				}
+
+				type Circle struct {
+					Kind   string ` + "`json:\"kind\"`" + `
+					Radius float64
+				}
+
+				func (Circle) isShape() {}
+			`),
+			pkg5: []byte(`
+				package models
+
+				type User struct {
+					ID   string
+					Name string
+				}
+			`),
 		},
 	}
 
-	pkgs, err := packages.Load(cfg, "file="+pkg1, "file="+pkg2, "file="+pkg3)
+	pkgs, err := packages.Load(cfg, "file="+pkg1, "file="+pkg2, "file="+pkg3, "file="+pkg4, "file="+pkg5)
 	if err != nil {
 		return nil, fmt.Errorf("error loading Go packages: %v\n%s", err, prefixLinesWithLineNumber(srcCode))
 	}
@@ -132,8 +161,8 @@ func testParser(srcCode string) (*parser.Parser, error) {
 		}
 	}
 
-	if len(pkgs) != 3 {
-		return nil, fmt.Errorf("expected 2 Go packages, got %v\n%s", len(pkgs), spew.Sdump(pkgs))
+	if len(pkgs) != 5 {
+		return nil, fmt.Errorf("expected 5 Go packages, got %v\n%s", len(pkgs), spew.Sdump(pkgs))
 	}
 
 	pkg := pkgs[0]
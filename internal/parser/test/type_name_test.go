@@ -0,0 +1,167 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTypeNames(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		directives string
+		typeNames  string // config
+		userDoc    string
+		out        []string
+		err        string
+	}{
+		{
+			out: []string{"User", "Response", "modelsUser", "v2ModelsUser"},
+		},
+		{
+			directives: "//gospeak:typenames path",
+			out:        []string{"User", "Response", "modelsUser", "v2ModelsUser"},
+		},
+		{
+			typeNames: "path",
+			out:       []string{"User", "Response", "modelsUser", "v2ModelsUser"},
+		},
+		{
+			userDoc: "//gospeak:name PublicUser",
+			out:     []string{"PublicUser", "Response", "modelsUser", "v2ModelsUser"},
+		},
+		{
+			userDoc: "//gospeak:name modelsUser",
+			err:     "type name modelsUser of github.com/golang-cz/gospeak/internal/parser/test/models.User collides with github.com/golang-cz/gospeak/internal/parser/test.User",
+		},
+		{
+			userDoc: "//gospeak:name Response",
+			err:     "type name Response of github.com/golang-cz/gospeak/internal/parser/test.User collides with github.com/golang-cz/gospeak/internal/parser/test.Response",
+		},
+		{
+			userDoc: "//gospeak:name public-user",
+			err:     `invalid //gospeak:name "public-user": expected identifier`,
+		},
+		{
+			directives: "//gospeak:typenames full",
+			err:        "invalid //gospeak:typenames full: expected package or path",
+		},
+		{
+			typeNames: "full",
+			err:       `invalid config TypeNames "full": expected package or path`,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		%s
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak/internal/parser/test/models"
+			modelsv2 "github.com/golang-cz/gospeak/internal/parser/test/v2/models"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			Test(ctx context.Context) (resp *Response, err error)
+		}
+
+		%s
+		type User struct {
+			ID int64
+		}
+
+		type Response struct {
+			User     *User
+			Models   *models.User
+			ModelsV2 *modelsv2.User
+		}
+		`, tc.directives, tc.userDoc)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		s, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, &gospeak.Config{TypeNames: tc.typeNames})
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s %s: expected error %q, got %v", tc.directives, tc.userDoc, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", tc.directives, tc.userDoc, err)
+			continue
+		}
+
+		var got []string
+		for _, typ := range s.Types {
+			got = append(got, typ.Name)
+		}
+		if !cmp.Equal(tc.out, got) {
+			t.Errorf("%s %s: types\n%s", tc.directives, tc.userDoc, coloredDiff(tc.out, got))
+		}
+	}
+}
+
+func TestTypeNamesOrder(t *testing.T) {
+	t.Parallel()
+
+	// Colliding types get the same names regardless of the order they're parsed in,
+	// ie. v2/models.User doesn't take the modelsUser path name of models.User.
+	tt := []string{
+		`
+		A(ctx context.Context) (user *modelsv2.User, err error)
+		B(ctx context.Context) (user *models.User, err error)
+		`,
+		`
+		A(ctx context.Context) (user *models.User, err error)
+		B(ctx context.Context) (user *modelsv2.User, err error)
+		`,
+	}
+
+	for _, methods := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak/internal/parser/test/models"
+			modelsv2 "github.com/golang-cz/gospeak/internal/parser/test/v2/models"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+		`, methods)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		s, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", methods, err)
+			continue
+		}
+
+		got := map[string]string{}
+		for _, method := range s.Services[0].Methods {
+			got[method.Outputs[0].Type.String()] = method.Outputs[0].Type.Struct.Type.Fields[0].Type.String()
+		}
+		want := map[string]string{"modelsUser": "int64", "v2ModelsUser": "string"}
+		if !cmp.Equal(want, got) {
+			t.Errorf("%s: type names\n%s", methods, coloredDiff(want, got))
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTypeOrder(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		methods string
+		out     []string
+	}{
+		{
+			methods: `
+				GetOrder(ctx context.Context, ID int64) (order *Order, err error)
+				GetUser(ctx context.Context, ID int64) (user *User, err error)
+			`,
+			out: []string{"User", "Order", "OrderShipping", "OrderItem", "modelsUser"},
+		},
+		{
+			// Unrelated method and reversed traversal order don't change the order of types.
+			methods: `
+				GetUser(ctx context.Context, ID int64) (user *User, err error)
+				Ping(ctx context.Context) (pong *Pong, err error)
+				GetOrder(ctx context.Context, ID int64) (order *Order, err error)
+			`,
+			out: []string{"User", "Order", "OrderShipping", "OrderItem", "Pong", "modelsUser"},
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak/internal/parser/test/models"
+		)
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		}
+
+		type User struct {
+			ID      int64
+			Profile *models.User
+		}
+
+		type Order struct {
+			ID       int64
+			Items    []*OrderItem
+			Customer *User
+			Shipping struct {
+				City string
+			}
+			Billing struct {
+				City string
+			} `+"`gospeak:\"typename=OrderShipping\"`"+`
+		}
+
+		type OrderItem struct {
+			ID    int64
+			Order *Order // recursive
+		}
+
+		type Pong struct{}
+		`, tc.methods)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		var want string
+		for i := 0; i < 5; i++ {
+			s, err := gospeak.ParseServices(p.Pkg, "TestAPI", []string{"TestAPI"}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, typ := range s.Types {
+				got = append(got, typ.Name)
+			}
+			if !cmp.Equal(tc.out, got) {
+				t.Errorf("types\n%s", coloredDiff(tc.out, got))
+			}
+
+			// Identical anonymous structs of the same name are parsed into a single schema type.
+			order := s.GetTypeByName("Order")
+			if shipping, billing := order.Fields[3].Type, order.Fields[4].Type; shipping.Struct.Type != billing.Struct.Type {
+				t.Errorf("expected identical anonymous structs to share a type, got %v and %v", shipping, billing)
+			}
+
+			// Byte-identical output across repeated runs.
+			json, err := s.ToJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 {
+				want = json
+			} else if json != want {
+				t.Fatalf("run %v: schema differs from the first run\n%s", i, coloredDiff(want, json))
+			}
+		}
+	}
+}
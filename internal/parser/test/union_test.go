@@ -0,0 +1,354 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+	"github.com/webrpc/webrpc/schema"
+)
+
+func TestUnion(t *testing.T) {
+	t.Parallel()
+
+	srcCode := `package test
+
+	import "context"
+
+	//go:webrpc json -out=/dev/null
+	type TestAPI interface{
+		GetDrawing(ctx context.Context) (drawing *Drawing, err error)
+	}
+
+	type Drawing struct {
+		Shapes []Shape
+	}
+
+	// Shape is one of the shapes below.
+	//gospeak:discriminator kind
+	type Shape interface {
+		isShape()
+	}
+
+	//gospeak:discriminator-value circle
+	type Circle struct {
+		Kind   string ` + "`json:\"kind\"`" + `
+		Radius float64
+		Label  string
+	}
+
+	func (Circle) isShape() {}
+
+	type Square struct {
+		Kind  string ` + "`json:\"kind\"`" + `
+		Side  float64
+		Label int
+	}
+
+	func (*Square) isShape() {}
+
+	// Unsealed interface stays any.
+	type Any interface {
+		String() string
+	}
+	`
+
+	p, err := testParser(srcCode)
+	if err != nil {
+		t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+	}
+
+	if err := parseInterface(p, "TestAPI"); err != nil {
+		t.Fatal(err)
+	}
+
+	var shape *schema.Type
+	var typeNames []string
+	discriminatorValues := map[string]any{}
+	for _, typ := range p.Schema.Types {
+		typeNames = append(typeNames, typ.Name)
+		if typ.Name == "Shape" {
+			shape = typ
+		}
+		for _, meta := range typ.TypeExtra.Meta {
+			if value, ok := meta["discriminatorValue"]; ok {
+				discriminatorValues[typ.Name] = value
+			}
+		}
+	}
+
+	wantTypeNames := []string{"Circle", "Square", "Shape", "Drawing"}
+	if !cmp.Equal(wantTypeNames, typeNames) {
+		t.Errorf("types\n%s", coloredDiff(wantTypeNames, typeNames))
+	}
+	if shape == nil {
+		t.Fatal("union type Shape not found")
+	}
+
+	wantMeta := []schema.TypeFieldMeta{
+		{"discriminator": "kind"},
+		{"oneOf": []string{"Circle", "Square"}},
+		{"mapping": map[string]string{"circle": "Circle", "Square": "Square"}},
+	}
+	if !cmp.Equal(wantMeta, shape.TypeExtra.Meta) {
+		t.Errorf("meta\n%s", coloredDiff(wantMeta, shape.TypeExtra.Meta))
+	}
+
+	wantDiscriminatorValues := map[string]any{"Circle": "circle", "Square": "Square"}
+	if !cmp.Equal(wantDiscriminatorValues, discriminatorValues) {
+		t.Errorf("discriminator values\n%s", coloredDiff(wantDiscriminatorValues, discriminatorValues))
+	}
+
+	var fields []string
+	for _, field := range shape.Fields {
+		fields = append(fields, fmt.Sprintf("%v:%v:%v", field.Name, field.Type, field.Optional))
+	}
+	wantFields := []string{
+		"kind:string:false",
+		"Radius:float64:true",
+		"Label:any:true", // string vs int
+		"Side:float64:true",
+	}
+	if !cmp.Equal(wantFields, fields) {
+		t.Errorf("fields\n%s", coloredDiff(wantFields, fields))
+	}
+
+	// Variants not referenced by any field are kept by method filters.
+	filter := gospeak.MethodFilter{Include: []string{"GetDrawing"}}
+	filtered := filter.Apply(p.Schema)
+	if len(filtered.Types) != len(p.Schema.Types) {
+		t.Errorf("filter dropped union variants: %v types left", len(filtered.Types))
+	}
+}
+
+func TestUnionErrors(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in  string
+		err string
+	}{
+		{
+			in: `
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+			`,
+			err: "union Shape: no implementations found",
+		},
+		{
+			in: `
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			type Circle struct {
+				Radius float64
+			}
+
+			func (Circle) isShape() {}
+			`,
+			err: `union Shape: variant Circle must have a string field "kind"`,
+		},
+		{
+			in: `
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			type Circle float64
+
+			func (Circle) isShape() {}
+			`,
+			err: "union Shape: variant Circle must be a struct",
+		},
+		{
+			in: `
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			//gospeak:discriminator-value
+			type Circle struct {
+				Kind string ` + "`json:\"kind\"`" + `
+			}
+
+			func (Circle) isShape() {}
+			`,
+			err: "union Shape: //gospeak:discriminator-value of variant Circle requires a value",
+		},
+		{
+			in: `
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			type Circle struct {
+				Kind string ` + "`json:\"kind\"`" + `
+			}
+
+			func (Circle) isShape() {}
+
+			//gospeak:discriminator-value Circle
+			type Ellipse struct {
+				Kind string ` + "`json:\"kind\"`" + `
+			}
+
+			func (Ellipse) isShape() {}
+			`,
+			err: `union Shape: variant Ellipse has the same discriminator value "Circle" as Circle`,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import "context"
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			GetShape(ctx context.Context) (shape Shape, err error)
+		}
+
+		%s
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestUnionVariants(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		in      string
+		oneOf   []string
+		mapping map[string]string
+		fields  []string
+		err     string
+	}{
+		{
+			// Variants of a union sealed by another package.
+			in: `
+			GetShape(ctx context.Context) (shape Shape, err error)
+			}
+
+			//gospeak:discriminator kind
+			type Shape interface{ models.Shape }
+			`,
+			oneOf:   []string{"modelsCircle"},
+			mapping: map[string]string{"modelsCircle": "modelsCircle"},
+			fields:  []string{"kind:string:false", "Radius:float64:true"},
+		},
+		{
+			// Recursive variant, parsed before the union.
+			in: `
+			GetGroup(ctx context.Context) (group *Group, err error)
+			}
+
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			type Group struct {
+				Kind   string ` + "`json:\"kind\"`" + `
+				Shapes []Shape
+			}
+
+			func (Group) isShape() {}
+
+			//gospeak:discriminator-value square
+			type Square struct {
+				Kind string ` + "`json:\"kind\"`" + `
+				Side float64
+			}
+
+			func (Square) isShape() {}
+			`,
+			oneOf:   []string{"Group", "Square"},
+			mapping: map[string]string{"Group": "Group", "square": "Square"},
+			fields:  []string{"kind:string:false", "Side:float64:true", "Shapes:[]Shape:true"},
+		},
+		{
+			// Recursive variant is validated too.
+			in: `
+			GetGroup(ctx context.Context) (group *Group, err error)
+			}
+
+			//gospeak:discriminator kind
+			type Shape interface{ isShape() }
+
+			type Group struct {
+				Shapes []Shape
+			}
+
+			func (Group) isShape() {}
+			`,
+			err: `union Shape: variant Group must have a string field "kind"`,
+		},
+	}
+
+	for _, tc := range tt {
+		srcCode := fmt.Sprintf(`package test
+
+		import (
+			"context"
+
+			"github.com/golang-cz/gospeak/internal/parser/test/models"
+		)
+
+		var _ models.Shape
+
+		//go:webrpc json -out=/dev/null
+		type TestAPI interface{
+			%s
+		`, tc.in)
+
+		p, err := testParser(srcCode)
+		if err != nil {
+			t.Fatal(fmt.Errorf("error creating test parser: %w", err))
+		}
+
+		err = parseInterface(p, "TestAPI")
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+
+		var shape *schema.Type
+		for _, typ := range p.Schema.Types {
+			if typ.Name == "Shape" {
+				shape = typ
+			}
+		}
+		if shape == nil {
+			t.Errorf("%s: union type Shape not found", tc.in)
+			continue
+		}
+
+		if oneOf := shape.TypeExtra.Meta[1]["oneOf"]; !cmp.Equal(tc.oneOf, oneOf) {
+			t.Errorf("%s: oneOf\n%s", tc.in, coloredDiff(tc.oneOf, oneOf))
+		}
+		if mapping := shape.TypeExtra.Meta[2]["mapping"]; !cmp.Equal(tc.mapping, mapping) {
+			t.Errorf("%s: mapping\n%s", tc.in, coloredDiff(tc.mapping, mapping))
+		}
+
+		var fields []string
+		for _, field := range shape.Fields {
+			fields = append(fields, fmt.Sprintf("%v:%v:%v", field.Name, field.Type, field.Optional))
+		}
+		if !cmp.Equal(tc.fields, fields) {
+			t.Errorf("%s: fields\n%s", tc.in, coloredDiff(tc.fields, fields))
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+)
+
+// Type naming strategies of types declared outside of the schema package.
+const (
+	// TypeNamesPackage prefixes types with their package name, ie. models.User => modelsUser.
+	// Colliding types fall back to TypeNamesPath, ie. v2/models.User => v2ModelsUser.
+	TypeNamesPackage = "package"
+
+	// TypeNamesPath prefixes types with their import path relative to the schema package,
+	// ie. github.com/acme/app/v2/models.User => v2ModelsUser.
+	TypeNamesPath = "path"
+)
+
+// CollectTypeNames collects the type naming strategy from package directives, ie.:
+//
+//	//gospeak:typenames path
+//	package proto
+func (p *Parser) CollectTypeNames() error {
+	for _, file := range p.Pkg.Syntax {
+		for _, commentGroup := range file.Comments {
+			for _, directive := range ParseDirectives(commentGroup) {
+				if directive.Name != "typenames" {
+					continue
+				}
+
+				if err := ValidateTypeNames(directive.Value); err != nil {
+					return p.errorf(directive.Pos, "invalid //gospeak:typenames %v: %v", directive.Value, err)
+				}
+
+				p.TypeNames = directive.Value
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateTypeNames returns an error if the given type naming strategy is unknown.
+func ValidateTypeNames(strategy string) error {
+	switch strategy {
+	case TypeNamesPackage, TypeNamesPath:
+		return nil
+	}
+	return fmt.Errorf("expected %v or %v", TypeNamesPackage, TypeNamesPath)
+}
+
+// Returns unique webrpc type name of the given named Go type. The name can be set
+// explicitly by //gospeak:name directive on type declarations in the schema package:
+//
+//	//gospeak:name PublicUser
+//	type User struct {}
+//
+// Returns an error if the name collides with another type.
+func (p *Parser) typeName(named *types.Named) (string, error) {
+	obj := named.Obj()
+	goType := obj.Name()
+	if obj.Pkg() != nil {
+		goType = obj.Pkg().Path() + "." + obj.Name()
+	}
+
+	name := p.GoTypeNameToWebrpc(p.GoTypeName(named))
+
+	if named.TypeArgs().Len() > 0 {
+		// Generic type instances are named after their type arguments, ie. gospeak.Page[*Pet] => PetPage.
+		// Instances differing in pointer type arguments only, ie. gospeak.Page[Pet] and gospeak.Page[*Pet],
+		// are identical in JSON, so they share the type. Other instances of the same name collide,
+		// ie. Result[[]Pet] and Result[PetList].
+		genericName, err := p.genericTypeName(named)
+		if err != nil {
+			return "", err
+		}
+		name = genericName
+		goType = genericTypeOwner(named)
+	} else if value, ok := findDirective(ParseDirectives(p.typeDoc(obj)), "name"); ok {
+		if !isIdentifier(value) {
+			return "", p.errorf(obj.Pos(), "invalid //gospeak:name %q: expected identifier", value)
+		}
+		name = value
+	} else if obj.Pkg() != nil && obj.Pkg().Path() != p.SchemaPkgName {
+		// Disambiguate colliding types, ie. models.User and v2/models.User => modelsUser and v2ModelsUser.
+		owner, taken := p.TypeNameOwners[name]
+		if p.TypeNames == TypeNamesPath || len(p.externalTypeNames()[name]) > 1 || (taken && owner != goType) {
+			name = p.pathTypeName(obj)
+		}
+	}
+
+	if owner, ok := p.TypeNameOwners[name]; ok && owner != goType {
+		diagnostic := p.errorf(obj.Pos(), "type name %v of %v collides with %v", name, goType, owner)
+		diagnostic.Fix = "rename the type with //gospeak:name <Name>"
+		if named.TypeArgs().Len() > 0 {
+			diagnostic.Fix = fmt.Sprintf("declare a named type for one of the instances, ie. type My%v %v", name, p.GoTypeName(named))
+		} else if obj.Pkg() != nil && obj.Pkg().Path() != p.SchemaPkgName {
+			diagnostic.Fix = "use //gospeak:typenames path, or rename the colliding type in the schema package with //gospeak:name <Name>"
+		}
+		return "", diagnostic
+	}
+	p.TypeNameOwners[name] = goType
+
+	return name, nil
+}
+
+// Returns fully qualified Go type of the generic type instance with pointer type arguments
+// dereferenced, ie. github.com/golang-cz/gospeak.Page[github.com/acme/app/proto.Pet]
+// for gospeak.Page[*Pet].
+func genericTypeOwner(named *types.Named) string {
+	obj := named.Obj()
+	args := make([]string, named.TypeArgs().Len())
+	for i := range args {
+		arg := named.TypeArgs().At(i)
+		if ptr, ok := arg.(*types.Pointer); ok {
+			arg = ptr.Elem()
+		}
+		args[i] = types.TypeString(arg, nil)
+	}
+	return fmt.Sprintf("%v.%v[%v]", obj.Pkg().Path(), obj.Name(), strings.Join(args, ","))
+}
+
+// Returns Go types declared outside of the schema package by their package-prefixed webrpc
+// type names, ie. modelsUser => [github.com/acme/app/models.User github.com/acme/app/v2/models.User].
+// Includes all the types reachable from the schema package, so colliding types are named
+// by their path regardless of the order in which they're parsed.
+func (p *Parser) externalTypeNames() map[string]map[string]struct{} {
+	if p.externalTypes != nil {
+		return p.externalTypes
+	}
+	p.externalTypes = map[string]map[string]struct{}{}
+
+	seen := map[types.Type]bool{}
+	var walk func(typ types.Type)
+	walk = func(typ types.Type) {
+		typ = types.Unalias(typ)
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+
+		switch t := typ.(type) {
+		case *types.Named:
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				walk(t.TypeArgs().At(i))
+			}
+			if obj := t.Obj(); obj.Pkg() != nil && obj.Pkg().Path() != p.SchemaPkgName && t.TypeArgs().Len() == 0 {
+				name := p.GoTypeNameToWebrpc(p.GoTypeName(t))
+				if p.externalTypes[name] == nil {
+					p.externalTypes[name] = map[string]struct{}{}
+				}
+				p.externalTypes[name][obj.Pkg().Path()+"."+obj.Name()] = struct{}{}
+			}
+			walk(t.Underlying())
+		case *types.Pointer:
+			walk(t.Elem())
+		case *types.Slice:
+			walk(t.Elem())
+		case *types.Array:
+			walk(t.Elem())
+		case *types.Map:
+			walk(t.Key())
+			walk(t.Elem())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				if field := t.Field(i); field.Exported() || field.Embedded() {
+					walk(field.Type())
+				}
+			}
+		}
+	}
+
+	for _, objects := range []map[*ast.Ident]types.Object{p.Pkg.TypesInfo.Defs, p.Pkg.TypesInfo.Uses} {
+		for _, obj := range objects {
+			if obj, ok := obj.(*types.TypeName); ok {
+				walk(obj.Type())
+			}
+		}
+	}
+
+	return p.externalTypes
+}
+
+// Returns type name prefixed with its import path relative to the schema package,
+// ie. github.com/acme/app/v2/models.User => v2ModelsUser for github.com/acme/app/proto schema.
+func (p *Parser) pathTypeName(obj *types.TypeName) string {
+	pkgPath := strings.Split(obj.Pkg().Path(), "/")
+	schemaPath := strings.Split(p.SchemaPkgName, "/")
+
+	// Strip the common path prefix, but keep at least the package name.
+	i := 0
+	for i < len(pkgPath)-1 && i < len(schemaPath) && pkgPath[i] == schemaPath[i] {
+		i++
+	}
+
+	var b strings.Builder
+	for _, elem := range pkgPath[i:] {
+		for _, word := range strings.FieldsFunc(elem, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+			if b.Len() == 0 {
+				b.WriteString(firstToLower(word))
+			} else {
+				b.WriteString(firstToUpper(word))
+			}
+		}
+	}
+	b.WriteString(obj.Name())
+
+	return b.String()
+}
+
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func firstToUpper(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
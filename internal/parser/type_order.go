@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"cmp"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Declaration of schema type, used to sort schema types deterministically.
+type typeDecl struct {
+	pkgPath string
+	pos     token.Pos
+}
+
+// Adds the type to the schema. The decl is the Go type name (for named types) or
+// the first field (for anonymous structs), which determines the type order.
+func (p *Parser) addType(typ *schema.Type, decl types.Object) {
+	p.Schema.Types = append(p.Schema.Types, typ)
+
+	if decl == nil {
+		return
+	}
+	var pkgPath string
+	if decl.Pkg() != nil {
+		pkgPath = decl.Pkg().Path()
+	}
+	p.typeDecls[typ] = typeDecl{pkgPath: pkgPath, pos: decl.Pos()}
+}
+
+// SortTypes sorts schema types in a stable order, so generated code diffs stay minimal
+// and don't depend on the order of methods or the traversal order of the parser:
+//
+//  1. types declared in the schema package, in declaration order,
+//  2. types declared in other packages (dependencies), by import path and declaration order,
+//  3. types with unknown declaration (ie. empty anonymous structs), by name.
+func (p *Parser) SortTypes() {
+	rank := func(typ *schema.Type) (int, string, token.Position) {
+		decl, ok := p.typeDecls[typ]
+		if !ok || !decl.pos.IsValid() {
+			return 2, "", token.Position{}
+		}
+		position := p.Pkg.Fset.Position(decl.pos)
+		if decl.pkgPath == p.SchemaPkgName || decl.pkgPath == "command-line-arguments" {
+			return 0, "", position
+		}
+		return 1, decl.pkgPath, position
+	}
+
+	slices.SortStableFunc(p.Schema.Types, func(a, b *schema.Type) int {
+		aRank, aPkg, aPos := rank(a)
+		bRank, bPkg, bPos := rank(b)
+		return cmp.Or(
+			cmp.Compare(aRank, bRank),
+			cmp.Compare(aPkg, bPkg),
+			cmp.Compare(aPos.Filename, bPos.Filename),
+			cmp.Compare(aPos.Offset, bPos.Offset),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
@@ -0,0 +1,283 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Returns discriminator field name if the given named interface is a sealed union, ie.:
+//
+//	//gospeak:discriminator kind
+//	type Shape interface {
+//		isShape()
+//	}
+//
+// The interface must have at least one unexported (marker) method, so it can only be
+// implemented within the package of the marker method.
+func (p *Parser) unionDiscriminator(named *types.Named, iface *types.Interface) (string, bool) {
+	sealed := false
+	for i := 0; i < iface.NumMethods(); i++ {
+		if !iface.Method(i).Exported() {
+			sealed = true
+			break
+		}
+	}
+	if !sealed {
+		return "", false
+	}
+
+	return findDirective(ParseDirectives(p.typeDoc(named.Obj())), "discriminator")
+}
+
+// ParseUnion parses a sealed interface into a discriminated union of all its implementations
+// (see unionVariants). Example:
+//
+//	//gospeak:discriminator kind
+//	type Shape interface{ isShape() }
+//
+//	type Circle struct {
+//		Kind   string `json:"kind"`
+//		Radius float64
+//	}
+//
+//	type Square struct {
+//		Kind string `json:"kind"`
+//		Side float64
+//	}
+//
+// The union is a struct of the discriminator field and all the variant fields (optional),
+// so it can be used by generators with no union support. The union type meta holds the
+// discriminator field name, the list of variants (oneOf) and the discriminator values
+// of the variants (mapping), so generators can emit tagged unions instead. Each variant
+// must have a string field with the discriminator JSON name.
+//
+// The discriminator value of a variant is its type name, ie. "Circle", unless given
+// by //gospeak:discriminator-value directive. The server must set the discriminator
+// field of the variant to this value:
+//
+//	//gospeak:discriminator-value circle
+//	type Circle struct { ... }
+func (p *Parser) ParseUnion(named *types.Named, iface *types.Interface, discriminator string) (*schema.VarType, error) {
+	webrpcTypeName, err := p.typeName(named)
+	if err != nil {
+		return nil, err
+	}
+
+	if discriminator == "" {
+		return nil, p.errorf(named.Obj().Pos(), "union %v: //gospeak:discriminator requires a field name", webrpcTypeName)
+	}
+
+	variants := p.unionVariants(iface)
+	if len(variants) == 0 {
+		diagnostic := p.errorf(named.Obj().Pos(), "union %v: no implementations found in package %v", webrpcTypeName, p.Pkg.Name)
+		diagnostic.Fix = "implement the interface by a struct type in the same package"
+		return nil, diagnostic
+	}
+
+	unionType := &schema.Type{
+		Kind: schema.TypeKind_Struct,
+		Name: webrpcTypeName,
+		Fields: []*schema.TypeField{
+			{
+				Name: discriminator,
+				Type: &schema.VarType{
+					Expr: "string",
+					Type: schema.T_String,
+				},
+			},
+		},
+	}
+
+	oneOf := make([]string, len(variants))
+	mapping := map[string]string{} // discriminator value => variant type name
+	for i, variant := range variants {
+		varType, err := p.ParseNamedType("", variant.Type())
+		if err != nil {
+			return nil, fmt.Errorf("union %v: failed to parse variant %v: %w", webrpcTypeName, variant.Name(), err)
+		}
+
+		addVariant := func(varType *schema.VarType) error {
+			if varType.Type != schema.T_Struct {
+				return p.errorf(variant.Pos(), "union %v: variant %v must be a struct, found %v", webrpcTypeName, variant.Name(), varType)
+			}
+			value, err := p.discriminatorValue(webrpcTypeName, variant, varType.Struct.Name)
+			if err != nil {
+				return err
+			}
+			if existing, ok := mapping[value]; ok {
+				return p.errorf(variant.Pos(), "union %v: variant %v has the same discriminator value %q as %v", webrpcTypeName, variant.Name(), value, existing)
+			}
+			mapping[value] = varType.Struct.Name
+			oneOf[i] = varType.Struct.Name
+			return p.addUnionVariant(unionType, discriminator, value, variant, varType.Struct.Type)
+		}
+
+		// The variant is still being parsed up the stack (recursive types). Add it once it's parsed.
+		if varType.Type == schema.T_Unknown {
+			p.pendingVariants[variant.Type()] = append(p.pendingVariants[variant.Type()], addVariant)
+			continue
+		}
+
+		if err := addVariant(varType); err != nil {
+			return nil, err
+		}
+	}
+
+	unionType.TypeExtra.Meta = []schema.TypeFieldMeta{
+		{"discriminator": discriminator},
+		{"oneOf": oneOf},
+		{"mapping": mapping},
+	}
+
+	p.addType(unionType, named.Obj())
+
+	return &schema.VarType{
+		Expr: webrpcTypeName,
+		Type: schema.T_Struct,
+		Struct: &schema.VarStructType{
+			Name: webrpcTypeName,
+			Type: unionType,
+		},
+	}, nil
+}
+
+// Returns discriminator value of the union variant given by //gospeak:discriminator-value
+// directive, defaulting to the variant type name.
+func (p *Parser) discriminatorValue(unionName string, variant *types.TypeName, typeName string) (string, error) {
+	value, ok := findDirective(ParseDirectives(p.typeDoc(variant)), "discriminator-value")
+	if !ok {
+		return typeName, nil
+	}
+	if value == "" {
+		return "", p.errorf(variant.Pos(), "union %v: //gospeak:discriminator-value of variant %v requires a value", unionName, variant.Name())
+	}
+	return value, nil
+}
+
+// Adds variant fields to the union type as optional fields. Fields of the same name
+// and different types across variants become any.
+func (p *Parser) addUnionVariant(unionType *schema.Type, discriminator string, value string, variant *types.TypeName, variantType *schema.Type) error {
+	if field := findField(variantType.Fields, discriminator); field == nil || field.Type.Type != schema.T_String {
+		diagnostic := p.errorf(variant.Pos(), "union %v: variant %v must have a string field %q", unionType.Name, variant.Name(), discriminator)
+		diagnostic.Fix = fmt.Sprintf("add field `json:%q` of string type", discriminator)
+		return diagnostic
+	}
+
+	variantType.TypeExtra.Meta = append(variantType.TypeExtra.Meta,
+		schema.TypeFieldMeta{"union": unionType.Name},
+		schema.TypeFieldMeta{"discriminatorValue": value},
+	)
+
+	for _, field := range variantType.Fields {
+		if field.Name == discriminator {
+			continue
+		}
+
+		existing := findField(unionType.Fields, field.Name)
+		if existing == nil {
+			unionField := *field
+			unionField.TypeExtra.Optional = true
+			unionType.Fields = append(unionType.Fields, &unionField)
+			continue
+		}
+
+		// Same field name with different types across variants.
+		if existing.Type.String() != field.Type.String() {
+			existing.Type = &schema.VarType{
+				Expr: "any",
+				Type: schema.T_Any,
+			}
+			existing.TypeExtra.Meta = withoutMeta(existing.TypeExtra.Meta, "go.field.type", "go.type.import")
+		}
+	}
+
+	return nil
+}
+
+// Returns all types implementing the given interface, ordered by package and declaration.
+//
+// Candidates are the types declared or referenced in the schema package (see types.Info)
+// and the types declared in the packages of the interface marker methods, ie. variants
+// of a union sealed by another package:
+//
+//	type Shape interface{ shapes.Sealed }
+func (p *Parser) unionVariants(iface *types.Interface) []*types.TypeName {
+	candidates := map[*types.TypeName]struct{}{}
+
+	for _, objects := range []map[*ast.Ident]types.Object{p.Pkg.TypesInfo.Defs, p.Pkg.TypesInfo.Uses} {
+		for _, obj := range objects {
+			if obj, ok := obj.(*types.TypeName); ok && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+				candidates[obj] = struct{}{}
+			}
+		}
+	}
+
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if method.Exported() || method.Pkg() == nil {
+			continue
+		}
+		scope := method.Pkg().Scope()
+		for _, name := range scope.Names() {
+			if obj, ok := scope.Lookup(name).(*types.TypeName); ok {
+				candidates[obj] = struct{}{}
+			}
+		}
+	}
+
+	var variants []*types.TypeName
+	for obj := range candidates {
+		if obj.IsAlias() {
+			continue
+		}
+		typ := obj.Type()
+		if types.IsInterface(typ) {
+			continue
+		}
+		if named, ok := typ.(*types.Named); ok && named.TypeParams().Len() > 0 {
+			continue // generic types must be instantiated
+		}
+		if types.Implements(typ, iface) || types.Implements(types.NewPointer(typ), iface) {
+			variants = append(variants, obj)
+		}
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		if variants[i].Pkg() != variants[j].Pkg() {
+			return variants[i].Pkg().Path() < variants[j].Pkg().Path()
+		}
+		return variants[i].Pos() < variants[j].Pos()
+	})
+
+	return variants
+}
+
+func findField(fields []*schema.TypeField, name string) *schema.TypeField {
+	for _, field := range fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// Returns a copy of meta without the given keys.
+func withoutMeta(meta []schema.TypeFieldMeta, keys ...string) []schema.TypeFieldMeta {
+	var filtered []schema.TypeFieldMeta
+	for _, m := range meta {
+		keep := true
+		for _, key := range keys {
+			if _, ok := m[key]; ok {
+				keep = false
+			}
+		}
+		if keep {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang-cz/gospeak/internal/rules"
+	"github.com/webrpc/webrpc/schema"
+)
+
+// Adds validation rules of `validate:"..."` and `gospeak:"..."` struct tags
+// to the field meta as OpenAPI constraints, ie.:
+//
+//	`validate:"required,min=1,max=64,email"` => {"minLength": 1}, {"maxLength": 64}, {"format": "email"}
+//	`gospeak:"min=0,max=100"`                => {"minimum": 0}, {"maximum": 100}
+//
+// Min/max rules constrain length of strings, number of list items and map
+// properties, or value of numbers. Rules after "dive" constrain list items
+// or map values and are not added. The `gospeak:"..."` rules always constrain
+// the field itself. The `validate:"..."` struct tag is kept
+// in "go.tag.validate" meta, so the Go generator can keep it too.
+func applyValidationRules(field *schema.TypeField, validateTag string, gospeakRules []rules.Rule) {
+	if validateTag != "" {
+		field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{"go.tag.validate": validateTag})
+	}
+
+	keys := constraintKeys(field.Type)
+
+	for _, rule := range rules.FieldRules(rules.Parse(validateTag), gospeakRules) {
+		switch rule.Name {
+		case "dive":
+			return
+		case "required":
+			field.TypeExtra.Optional = false
+		case "min", "gte":
+			addConstraint(field, keys.min, rule.Param, 0)
+		case "max", "lte":
+			addConstraint(field, keys.max, rule.Param, 0)
+		case "len":
+			addConstraint(field, keys.min, rule.Param, 0)
+			addConstraint(field, keys.max, rule.Param, 0)
+		case "gt":
+			if keys.min == "minimum" {
+				addConstraint(field, "exclusiveMinimum", rule.Param, 0)
+			} else {
+				addConstraint(field, keys.min, rule.Param, 1) // length > n => minLength n+1
+			}
+		case "lt":
+			if keys.max == "maximum" {
+				addConstraint(field, "exclusiveMaximum", rule.Param, 0)
+			} else {
+				addConstraint(field, keys.max, rule.Param, -1) // length < n => maxLength n-1
+			}
+		case "oneof":
+			field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{"enum": strings.Fields(rule.Param)})
+		case "pattern":
+			field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{"pattern": rule.Param})
+		default:
+			if format, ok := rules.Formats[rule.Name]; ok {
+				field.TypeExtra.Meta = append(withoutMeta(field.TypeExtra.Meta, "format"), schema.TypeFieldMeta{"format": format})
+			}
+			if pattern, ok := rules.Patterns[rule.Name]; ok {
+				field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{"pattern": pattern})
+			}
+		}
+	}
+}
+
+// OpenAPI min/max constraint keywords of the given type.
+type constraintKeywords struct {
+	min string
+	max string
+}
+
+func constraintKeys(varType *schema.VarType) constraintKeywords {
+	if varType == nil {
+		return constraintKeywords{}
+	}
+
+	switch varType.Type {
+	case schema.T_String:
+		return constraintKeywords{min: "minLength", max: "maxLength"}
+	case schema.T_List:
+		return constraintKeywords{min: "minItems", max: "maxItems"}
+	case schema.T_Map:
+		return constraintKeywords{min: "minProperties", max: "maxProperties"}
+	case schema.T_Int, schema.T_Int8, schema.T_Int16, schema.T_Int32, schema.T_Int64,
+		schema.T_Uint, schema.T_Uint8, schema.T_Uint16, schema.T_Uint32, schema.T_Uint64,
+		schema.T_Float32, schema.T_Float64:
+		return constraintKeywords{min: "minimum", max: "maximum"}
+	}
+
+	return constraintKeywords{}
+}
+
+// Adds numeric constraint meta, ie. {"maxLength": 64}. Integer params are added as int64.
+func addConstraint(field *schema.TypeField, key string, param string, delta int64) {
+	if key == "" {
+		return
+	}
+
+	if n, err := strconv.ParseInt(param, 10, 64); err == nil {
+		field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{key: n + delta})
+		return
+	}
+
+	if f, err := strconv.ParseFloat(param, 64); err == nil && delta == 0 && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		field.TypeExtra.Meta = append(field.TypeExtra.Meta, schema.TypeFieldMeta{key: f})
+	}
+}
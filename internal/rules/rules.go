@@ -0,0 +1,132 @@
+// Package rules parses validation rules from `validate:"..."` and `gospeak:"..."` struct tags.
+// It's shared by the schema parser and the runtime validate package.
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule is a single validation rule, ie. min=1 or email.
+type Rule struct {
+	Name  string // min
+	Param string // 1
+}
+
+func (r Rule) String() string {
+	if r.Param == "" {
+		return r.Name
+	}
+	return r.Name + "=" + r.Param
+}
+
+// Formats are rules validating string format. Maps rule name to OpenAPI format.
+var Formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"ip":       "ip",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+}
+
+// Patterns are rules validating string by regular expression. Maps rule name to the pattern.
+var Patterns = map[string]string{
+	"alpha":    `^[a-zA-Z]+$`,
+	"alphanum": `^[a-zA-Z0-9]+$`,
+	"numeric":  `^[-+]?[0-9]+(?:\.[0-9]+)?$`,
+}
+
+// Parse parses validation rules of `validate:"required,min=1,max=64,email"` struct tag,
+// compatible with github.com/go-playground/validator syntax. Commas and pipes in params
+// must be escaped as 0x2C and 0x7C, ie. `validate:"pattern=^[a-z]{10x2C3}$"`.
+//
+// Rules unknown to gospeak are returned too, so they're kept for 3rd party validators.
+func Parse(tag string) []Rule {
+	var rules []Rule
+	for _, option := range strings.Split(tag, ",") {
+		if option = strings.TrimSpace(option); option == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(option, "=")
+		rules = append(rules, Rule{Name: name, Param: unescape(param)})
+	}
+	return rules
+}
+
+// FieldRules returns the validate rules with the gospeak rules inserted before
+// "dive", so they constrain the field itself rather than its items.
+func FieldRules(validateRules []Rule, gospeakRules []Rule) []Rule {
+	i := 0
+	for i < len(validateRules) && validateRules[i].Name != "dive" {
+		i++
+	}
+
+	fieldRules := make([]Rule, 0, len(validateRules)+len(gospeakRules))
+	fieldRules = append(fieldRules, validateRules[:i]...)
+	fieldRules = append(fieldRules, gospeakRules...)
+	return append(fieldRules, validateRules[i:]...)
+}
+
+// GospeakOption converts `gospeak:"..."` struct tag option to a validation rule, ie.:
+//
+//	`gospeak:"min=1,max=64,format=email"`
+//	`gospeak:"pattern=^[a-z]+$"`
+//	`gospeak:"oneof=asc desc"`
+//
+// Returns false if the option is not a validation rule.
+func GospeakOption(name string, param string) (Rule, bool, error) {
+	switch name {
+	case "min", "max", "len":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return Rule{}, true, fmt.Errorf("%v=%v: expected number", name, param)
+		}
+		return Rule{Name: name, Param: param}, true, nil
+	case "pattern", "oneof":
+		return Rule{Name: name, Param: unescape(param)}, true, nil
+	case "format":
+		if _, ok := Formats[param]; !ok {
+			return Rule{}, true, fmt.Errorf("format=%v: unknown format", param)
+		}
+		return Rule{Name: param}, true, nil
+	}
+	return Rule{}, false, nil
+}
+
+// SplitOptions splits comma-separated tag options, while keeping commas
+// nested in type expressions, ie. "type=map<string,int>,optional".
+func SplitOptions(value string) []string {
+	var options []string
+
+	depth, start := 0, 0
+	for i, char := range value {
+		switch char {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				options = append(options, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	options = append(options, value[start:])
+
+	var nonEmpty []string
+	for _, option := range options {
+		if option = strings.TrimSpace(option); option != "" {
+			nonEmpty = append(nonEmpty, option)
+		}
+	}
+
+	return nonEmpty
+}
+
+func unescape(param string) string {
+	return strings.NewReplacer("0x2C", ",", "0x7C", "|").Replace(param)
+}
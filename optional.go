@@ -0,0 +1,121 @@
+package gospeak
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Optional is a value that can be absent, but not null. Use it for PATCH-style
+// APIs, where an absent field means "don't change" and a null is not allowed.
+//
+//	type UpdateUserRequest struct {
+//		Name gospeak.Optional[string] `json:"name,omitzero"`
+//	}
+//
+// Optional fields require `json:",omitzero"` (Go 1.24+), so the absent value is
+// omitted from JSON rather than encoded as null. Combine with Nullable to allow null values
+// too, ie. Optional[Nullable[string]] distinguishes absent vs. null vs. value.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+// OptionalOf returns Optional with the given value set.
+func OptionalOf[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and reports whether it was set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+// IsZero reports whether the value is absent. Used by `json:",omitzero"`.
+func (o Optional[T]) IsZero() bool {
+	return !o.Set
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It's called only if the field is
+// present in JSON. Null is rejected, unless T is Nullable[T].
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if isNull(data) {
+		if _, ok := any(&o.Value).(nullable); !ok {
+			return fmt.Errorf("null is not allowed for optional %T value, use Nullable", o.Value)
+		}
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*o = OptionalOf(v)
+	return nil
+}
+
+// Nullable is a value that can be null. It's always present in JSON.
+//
+//	type User struct {
+//		DeletedAt gospeak.Nullable[time.Time] `json:"deletedAt"`
+//	}
+type Nullable[T any] struct {
+	Value T
+	Valid bool
+}
+
+// NullableOf returns valid (non-null) Nullable with the given value.
+func NullableOf[T any](v T) Nullable[T] {
+	return Nullable[T]{Value: v, Valid: true}
+}
+
+// Null returns null Nullable.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{}
+}
+
+// Get returns the value and reports whether it's non-null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Valid
+}
+
+// MarshalJSON implements json.Marshaler.
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// nullable is implemented by Nullable[T] only, so Optional[Nullable[T]] can tell it accepts null.
+type nullable interface {
+	isNullable()
+}
+
+func (n *Nullable[T]) isNullable() {}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if isNull(data) {
+		*n = Null[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NullableOf(v)
+	return nil
+}
+
+func isNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
@@ -0,0 +1,74 @@
+package gospeak
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionalNullable(t *testing.T) {
+	type Patch struct {
+		Name  Optional[string]           `json:"name,omitzero"`
+		Email Optional[Nullable[string]] `json:"email,omitzero"`
+		Age   Nullable[int]              `json:"age"`
+	}
+
+	tt := []struct {
+		in  string
+		out Patch
+		err bool
+	}{
+		{in: `{}`, out: Patch{}},
+		{in: `{"name":"x","age":5}`, out: Patch{Name: OptionalOf("x"), Age: NullableOf(5)}},
+		{in: `{"email":null,"age":null}`, out: Patch{Email: OptionalOf(Null[string]())}},
+		{in: `{"email":"a@b.c"}`, out: Patch{Email: OptionalOf(NullableOf("a@b.c"))}},
+		{in: `{"name":null}`, err: true},
+		{in: `{"name":1}`, err: true},
+	}
+
+	for _, tc := range tt {
+		var got Patch
+		err := json.Unmarshal([]byte(tc.in), &got)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.in, err)
+			continue
+		}
+		if got != tc.out {
+			t.Errorf("%s: expected %+v, got %+v", tc.in, tc.out, got)
+		}
+	}
+
+	// Null is rejected for json.Unmarshaler values other than Nullable.
+	var raw Optional[json.RawMessage]
+	if err := json.Unmarshal([]byte(`null`), &raw); err == nil {
+		t.Errorf("expected null error for Optional[json.RawMessage], got %+v", raw)
+	}
+}
+
+func TestOptionalNullableMarshal(t *testing.T) {
+	tt := []struct {
+		in  any
+		out string
+	}{
+		{in: OptionalOf(5), out: `5`},
+		{in: Optional[int]{}, out: `null`},
+		{in: NullableOf("x"), out: `"x"`},
+		{in: Null[string](), out: `null`},
+		{in: OptionalOf(Null[int]()), out: `null`},
+	}
+
+	for _, tc := range tt {
+		b, err := json.Marshal(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.out {
+			t.Errorf("%+v: expected %s, got %s", tc.in, tc.out, b)
+		}
+	}
+}
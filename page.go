@@ -0,0 +1,183 @@
+package gospeak
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Page is a page of list method results, ie.:
+//
+//	type PetAPI interface {
+//		ListPets(ctx context.Context, cursor gospeak.Cursor, limit int) (page *gospeak.Page[*Pet], err error)
+//	}
+//
+// The parser names the schema type after the items, ie. PetPage, and annotates
+// the method as "paginated", so generated clients can walk all pages.
+type Page[T any] struct {
+	Items []T `json:"items"`
+
+	// NextCursor is empty on the last page.
+	NextCursor Cursor `json:"nextCursor,omitempty"`
+}
+
+// HasNext reports whether there is a next page.
+func (p *Page[T]) HasNext() bool {
+	return p != nil && p.NextCursor != ""
+}
+
+// Cursor is an opaque pagination cursor. Clients pass it back as is to get
+// the next page. Use CursorCodec to encode and sign the server-side position.
+// Empty cursor requests the first page.
+type Cursor string
+
+// ErrInvalidCursor is returned by CursorCodec.Decode() for malformed or tampered cursors.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorCodec encodes the server-side position, ie. last seen ID, into an opaque
+// cursor signed by HMAC-SHA256, so clients can't tamper with it. The purpose,
+// ie. the method name, is signed too, so a cursor of one list method can't be
+// passed to another one:
+//
+//	var petCursors = gospeak.NewCursorCodec(secretKey, "ListPets")
+//
+//	type petsPosition struct {
+//		LastID int64 `json:"id"`
+//	}
+//
+//	func (s *Server) ListPets(ctx context.Context, cursor gospeak.Cursor, limit int) (*gospeak.Page[*Pet], error) {
+//		var pos petsPosition
+//		if err := petCursors.Decode(cursor, &pos); err != nil {
+//			return nil, proto.ErrWebrpcBadRequest.WithCause(err)
+//		}
+//		pets, err := s.DB.ListPets(ctx, pos.LastID, limit)
+//		...
+//		page := &gospeak.Page[*Pet]{Items: pets}
+//		if len(pets) == limit {
+//			page.NextCursor, err = petCursors.Encode(petsPosition{LastID: pets[len(pets)-1].ID})
+//		}
+//		return page, err
+//	}
+//
+// The cursor is not encrypted, so don't put any secrets into the position.
+type CursorCodec struct {
+	key     []byte
+	purpose string
+}
+
+// MinCursorKeySize is the minimum size of CursorCodec secret key in bytes.
+const MinCursorKeySize = 32
+
+// NewCursorCodec returns CursorCodec signing cursors of the given purpose, ie. the
+// list method name, with the given secret key. It panics if the key is shorter than
+// MinCursorKeySize, so a missing key fails at startup rather than issuing forgeable
+// cursors.
+func NewCursorCodec(key []byte, purpose string) *CursorCodec {
+	if len(key) < MinCursorKeySize {
+		panic(fmt.Sprintf("gospeak: cursor key must be at least %v bytes, got %v", MinCursorKeySize, len(key)))
+	}
+	return &CursorCodec{key: bytes.Clone(key), purpose: purpose}
+}
+
+// Encode encodes the position as JSON into a signed cursor.
+func (c *CursorCodec) Encode(position any) (Cursor, error) {
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("encoding cursor: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(c.sign(payload))
+
+	return Cursor(encoded + "." + signature), nil
+}
+
+// Decode verifies the cursor signature and decodes the position. Empty cursor is
+// the first page, so the position is left untouched. Returns ErrInvalidCursor, if
+// the cursor is malformed, tampered with or issued for another purpose.
+func (c *CursorCodec) Decode(cursor Cursor, position any) error {
+	if cursor == "" {
+		return nil
+	}
+
+	encoded, encodedSignature, ok := strings.Cut(string(cursor), ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(payload, position); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return nil
+}
+
+// Signs the length-prefixed purpose followed by the payload.
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(binary.AppendUvarint(nil, uint64(len(c.purpose))))
+	mac.Write([]byte(c.purpose))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// WalkPages calls fn for all items of all pages, starting with the first page, ie.:
+//
+//	err := gospeak.WalkPages(ctx, func(ctx context.Context, cursor gospeak.Cursor) (*gospeak.Page[*Pet], error) {
+//		return client.ListPets(ctx, cursor, 100)
+//	}, func(pet *Pet) error {
+//		fmt.Println(pet.Name)
+//		return nil
+//	})
+//
+// Stops on the first error returned by fetch or fn, or when the context is canceled.
+func WalkPages[T any](ctx context.Context, fetch func(ctx context.Context, cursor Cursor) (*Page[T], error), fn func(item T) error) error {
+	var cursor Cursor
+	seen := map[Cursor]bool{}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := fetch(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			return nil
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if !page.HasNext() {
+			return nil
+		}
+		if seen[page.NextCursor] {
+			return fmt.Errorf("walking pages: cursor %q repeated", page.NextCursor)
+		}
+		seen[page.NextCursor] = true
+		cursor = page.NextCursor
+	}
+}
@@ -0,0 +1,152 @@
+package gospeak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type petsPosition struct {
+	LastID int64 `json:"id"`
+}
+
+func TestCursorCodec(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	codec := NewCursorCodec(key, "ListPets")
+
+	cursor, err := codec.Encode(petsPosition{LastID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pos petsPosition
+	if err := codec.Decode(cursor, &pos); err != nil {
+		t.Fatal(err)
+	}
+	if pos.LastID != 42 {
+		t.Errorf("expected LastID 42, got %v", pos.LastID)
+	}
+
+	// Empty cursor is the first page.
+	pos = petsPosition{}
+	if err := codec.Decode("", &pos); err != nil || pos.LastID != 0 {
+		t.Errorf("expected empty position, got %v, %v", pos, err)
+	}
+
+	payload, signature, _ := strings.Cut(string(cursor), ".")
+	forged, _ := NewCursorCodec([]byte("another 32 bytes long secret key"), "ListPets").Encode(petsPosition{LastID: 1})
+	replayed, _ := NewCursorCodec(key, "ListOrders").Encode(petsPosition{LastID: 1})
+
+	invalid := []Cursor{
+		"garbage",
+		Cursor(payload),
+		Cursor(payload + "." + signature[1:]),
+		Cursor(payload[1:] + "." + signature),
+		Cursor(strings.Replace(string(cursor), payload, "eyJpZCI6MX0", 1)), // {"id":1} with the original signature
+		forged,
+		replayed,
+	}
+	for _, cursor := range invalid {
+		err := codec.Decode(cursor, &pos)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Decode(%q): expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
+
+func TestNewCursorCodecShortKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range [][]byte{nil, []byte("secret"), make([]byte, MinCursorKeySize-1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewCursorCodec(%q): expected panic", key)
+				}
+			}()
+			NewCursorCodec(key, "ListPets")
+		}()
+	}
+}
+
+func TestPageJSON(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		page Page[string]
+		json string
+	}{
+		{Page[string]{Items: []string{"a", "b"}, NextCursor: "next"}, `{"items":["a","b"],"nextCursor":"next"}`},
+		{Page[string]{Items: []string{}}, `{"items":[]}`},
+	}
+
+	for _, tc := range tt {
+		data, err := json.Marshal(tc.page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tc.json {
+			t.Errorf("expected %s, got %s", tc.json, data)
+		}
+	}
+}
+
+func TestWalkPages(t *testing.T) {
+	t.Parallel()
+
+	codec := NewCursorCodec([]byte("0123456789abcdef0123456789abcdef"), "ListItems")
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	// Server returning pages of 3 items.
+	fetch := func(ctx context.Context, cursor Cursor) (*Page[int], error) {
+		var offset int
+		if err := codec.Decode(cursor, &offset); err != nil {
+			return nil, err
+		}
+		end := min(offset+3, len(items))
+		page := &Page[int]{Items: items[offset:end]}
+		if end < len(items) {
+			page.NextCursor, _ = codec.Encode(end)
+		}
+		return page, nil
+	}
+
+	var got []int
+	err := WalkPages(context.Background(), fetch, func(item int) error {
+		got = append(got, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(items) {
+		t.Errorf("expected %v, got %v", items, got)
+	}
+
+	// Stops on the first error.
+	errStop := errors.New("stop")
+	got = nil
+	err = WalkPages(context.Background(), fetch, func(item int) error {
+		if item == 4 {
+			return errStop
+		}
+		got = append(got, item)
+		return nil
+	})
+	if !errors.Is(err, errStop) || fmt.Sprint(got) != "[1 2 3]" {
+		t.Errorf("expected to stop at item 4, got %v, %v", got, err)
+	}
+
+	// Detects repeated cursor.
+	err = WalkPages(context.Background(), func(ctx context.Context, cursor Cursor) (*Page[int], error) {
+		return &Page[int]{NextCursor: "same"}, nil
+	}, func(item int) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "repeated") {
+		t.Errorf("expected repeated cursor error, got %v", err)
+	}
+}
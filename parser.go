@@ -2,12 +2,15 @@ package gospeak
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/golang-cz/gospeak/internal/parser"
@@ -18,16 +21,48 @@ import (
 //go:embed errors.go
 var webrpcErrorsSourceCode string
 
+// Diagnostic is a parser error with a source position of the offending method, field or type.
+type Diagnostic = parser.Diagnostic
+
+// Diagnostics is a list of all errors found while parsing, sorted by position.
+// Returned by Parse(), so library users can get the structured errors via errors.As().
+type Diagnostics = parser.Diagnostics
+
 type Target struct {
 	Schema        *schema.WebRPCSchema
 	Generator     string
 	InterfaceName string
 	OutFile       string
 	Opts          map[string]interface{}
+	Filter        MethodFilter // Per-target method filter, ie. -include=Get*,List* -exclude-annotated=internal.
+	Services      []string     // Interfaces generated as services of a single schema, ie. -services=UserAPI,BillingAPI. Defaults to InterfaceName.
+	Constants     bool         // Add constants exported by //gospeak:export to the schema, ie. -constants.
+}
+
+// Config holds optional parser settings.
+type Config struct {
+	// TypeMappings maps named Go types to webrpc types, ie. "github.com/shopspring/decimal.Decimal": "string".
+	// Takes precedence over //gospeak:map package directives.
+	TypeMappings map[string]string
+
+	// TypeNames is the naming strategy of types declared outside of the schema package:
+	// "package" (default) prefixes types with package name, ie. models.User => modelsUser,
+	// "path" prefixes types with import path relative to the schema package, ie. v2/models.User => v2ModelsUser.
+	// Takes precedence over //gospeak:typenames package directive.
+	TypeNames string
+
+	// Constants adds constants exported by //gospeak:export directive to the schema as "Constants" type.
+	// Only generators that know the type should opt in, see the -constants target flag.
+	Constants bool
 }
 
 // Parse Go source file or package folder and return WebRPC schema.
 func Parse(filePath string) ([]*Target, error) {
+	return ParseWithConfig(filePath, nil)
+}
+
+// ParseWithConfig parses Go source file or package folder with the given config and returns WebRPC schema.
+func ParseWithConfig(filePath string, config *Config) ([]*Target, error) {
 	dir, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get directory from %q: %w", dir, err)
@@ -70,24 +105,13 @@ func Parse(filePath string) ([]*Target, error) {
 		return nil, fmt.Errorf("failed to load Go packages from %q: %w", dir, err)
 	}
 
-	// Print all errors.
-	for _, pkg := range pkgs {
-		for _, pkgErr := range pkg.Errors {
-			fmt.Fprintln(os.Stderr, pkgErr)
-		}
-
-		for _, typeErr := range pkg.TypeErrors {
-			fmt.Fprintln(os.Stderr, typeErr)
-		}
-	}
-
 	if len(pkgs) != 1 {
 		return nil, fmt.Errorf("failed to load Go package (len=%v) from %q", len(pkgs), dir)
 	}
 	pkg := pkgs[0]
 
-	if len(pkg.Errors) > 0 || len(pkg.TypeErrors) > 0 {
-		return nil, fmt.Errorf("%v errors", len(pkg.Errors)+len(pkg.TypeErrors))
+	if diagnostics := packageDiagnostics(pkg); len(diagnostics) > 0 {
+		return nil, diagnostics
 	}
 
 	// Collect Go interfaces with `//go:webrpc` comments.
@@ -98,38 +122,155 @@ func Parse(filePath string) ([]*Target, error) {
 
 	cache := map[string]*schema.WebRPCSchema{}
 	for _, target := range targets {
-		if interfaceSchema, ok := cache[target.InterfaceName]; ok {
-			// Hit.
-			target.Schema = interfaceSchema
+		targetConfig := &Config{}
+		if config != nil {
+			*targetConfig = *config
+		}
+		targetConfig.Constants = targetConfig.Constants || target.Constants
+
+		// The schema is named after the target interface, see ParseServices().
+		cacheKey := fmt.Sprintf("%v:%v:%v", target.InterfaceName, strings.Join(target.Services, ","), targetConfig.Constants)
+		if interfaceSchema, ok := cache[cacheKey]; ok {
+			// Hit. The cached schema is shared across targets, so it must not be modified.
+			target.Schema = target.Filter.Apply(interfaceSchema)
+			continue
 		}
 
 		// Miss.
-		p := parser.New(pkg)
-		p.Schema.SchemaName = target.InterfaceName
+		interfaceSchema, err := ParseServices(pkg, target.InterfaceName, target.Services, targetConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		target.Schema = target.Filter.Apply(interfaceSchema)
+		cache[cacheKey] = interfaceSchema
+	}
 
-		if err := p.CollectEnums(); err != nil {
-			return nil, fmt.Errorf("collecting enums: %w", err)
+	return targets, nil
+}
+
+// ParseServices parses the given Go interfaces into a single WebRPC schema,
+// one service per interface. The services share all the schema types.
+func ParseServices(pkg *packages.Package, schemaName string, interfaceNames []string, config *Config) (*schema.WebRPCSchema, error) {
+	p := parser.New(pkg)
+	p.Schema.SchemaName = schemaName
+
+	if err := p.CollectEnums(); err != nil {
+		var diagnostic Diagnostic
+		if errors.As(err, &diagnostic) {
+			return nil, Diagnostics{diagnostic}
+		}
+		return nil, fmt.Errorf("collecting enums: %w", err)
+	}
+
+	if err := p.CollectTypeMappings(); err != nil {
+		var diagnostic Diagnostic
+		if errors.As(err, &diagnostic) {
+			return nil, Diagnostics{diagnostic}
 		}
+		return nil, fmt.Errorf("collecting type mappings: %w", err)
+	}
+
+	if err := p.CollectTypeNames(); err != nil {
+		var diagnostic Diagnostic
+		if errors.As(err, &diagnostic) {
+			return nil, Diagnostics{diagnostic}
+		}
+		return nil, fmt.Errorf("collecting type names: %w", err)
+	}
+
+	if config != nil {
+		maps.Copy(p.TypeMappings, config.TypeMappings)
+		if config.TypeNames != "" {
+			if err := parser.ValidateTypeNames(config.TypeNames); err != nil {
+				return nil, fmt.Errorf("invalid config TypeNames %q: %w", config.TypeNames, err)
+			}
+			p.TypeNames = config.TypeNames
+		}
+		p.ExportConstants = config.Constants
+	}
+
+	// Collect errors from all interfaces, so we can report them at once.
+	var diagnostics Diagnostics
 
-		obj := pkg.Types.Scope().Lookup(target.InterfaceName)
+	for _, interfaceName := range interfaceNames {
+		obj := pkg.Types.Scope().Lookup(interfaceName)
 		if obj == nil {
-			return nil, fmt.Errorf("type interface %v{} not found", target.InterfaceName)
+			return nil, fmt.Errorf("type interface %v{} not found", interfaceName)
 		}
 
 		iface, ok := obj.Type().Underlying().(*types.Interface)
 		if !ok {
-			return nil, fmt.Errorf("type %v{} is %T", target.InterfaceName, obj.Type().Underlying())
+			return nil, Diagnostic{
+				Pos: pkg.Fset.Position(obj.Pos()),
+				Msg: fmt.Sprintf("type %v{} is %T", interfaceName, obj.Type().Underlying()),
+			}
+		}
+
+		if err := p.ParseInterfaceMethods(iface, interfaceName); err != nil {
+			var interfaceDiagnostics Diagnostics
+			if !errors.As(err, &interfaceDiagnostics) {
+				return nil, fmt.Errorf("failed to parse interface %q: %w", interfaceName, err)
+			}
+			diagnostics = append(diagnostics, interfaceDiagnostics...)
 		}
+	}
 
-		if err := p.ParseInterfaceMethods(iface, target.InterfaceName); err != nil {
-			return nil, fmt.Errorf("failed to parse interface %q: %w", target.InterfaceName, err)
+	// Collect constants after the services, so their types are named consistently with the method types.
+	if err := p.CollectConstants(); err != nil {
+		var constantsDiagnostics Diagnostics
+		var diagnostic Diagnostic
+		switch {
+		case errors.As(err, &constantsDiagnostics):
+			diagnostics = append(diagnostics, constantsDiagnostics...)
+		case errors.As(err, &diagnostic):
+			diagnostics = append(diagnostics, diagnostic)
+		default:
+			return nil, fmt.Errorf("collecting constants: %w", err)
 		}
+	}
 
-		target.Schema = p.Schema
-		cache[target.InterfaceName] = p.Schema
+	if len(diagnostics) > 0 {
+		diagnostics.Sort()
+		return nil, diagnostics
 	}
 
-	return targets, nil
+	p.SortTypes()
+
+	return p.Schema, nil
+}
+
+// Returns Go package loading and type-checking errors as diagnostics.
+func packageDiagnostics(pkg *packages.Package) Diagnostics {
+	var diagnostics Diagnostics
+
+	for _, pkgErr := range pkg.Errors {
+		diagnostics = append(diagnostics, Diagnostic{
+			Pos: parser.ParsePosition(pkgErr.Pos),
+			Msg: pkgErr.Msg,
+		})
+	}
+
+	// Type errors are also reported in pkg.Errors. Add only those that weren't.
+	// Compare by line and column, as pkg.Errors positions have no offset.
+	for _, typeErr := range pkg.TypeErrors {
+		diagnostic := Diagnostic{
+			Pos: typeErr.Fset.Position(typeErr.Pos),
+			Msg: typeErr.Msg,
+		}
+		reported := slices.ContainsFunc(diagnostics, func(d Diagnostic) bool {
+			return d.Pos.Filename == diagnostic.Pos.Filename &&
+				d.Pos.Line == diagnostic.Pos.Line &&
+				d.Pos.Column == diagnostic.Pos.Column &&
+				d.Msg == diagnostic.Msg
+		})
+		if !reported {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+
+	diagnostics.Sort()
+	return diagnostics
 }
 
 // Find all Go interfaces with the special //go:webrpc comments.
@@ -148,9 +289,12 @@ func CollectInterfaces(pkg *packages.Package) ([]*Target, error) {
 									if webrpcCmd, hasPrefix := strings.CutPrefix(comment.Text, "//go:webrpc "); hasPrefix {
 										target, err := parseWebrpcCommand(webrpcCmd)
 										if err != nil {
-											return nil, fmt.Errorf("failed to parse %s", comment.Text)
+											return nil, fmt.Errorf("failed to parse %s: %w", comment.Text, err)
 										}
 										target.InterfaceName = typeSpec.Name.Name
+										if len(target.Services) == 0 {
+											target.Services = []string{target.InterfaceName}
+										}
 										targets = append(targets, target)
 									}
 								}
@@ -178,9 +322,28 @@ func parseWebrpcCommand(cmd string) (*Target, error) {
 			name = strings.TrimLeft(name, "-")
 
 			// target options
-			if name == "out" {
+			switch name {
+			case "out":
 				target.OutFile = value
-			} else {
+			case "include":
+				target.Filter.Include = splitList(value)
+			case "exclude":
+				target.Filter.Exclude = splitList(value)
+			case "include-annotated":
+				target.Filter.IncludeAnnotated = splitList(value)
+			case "exclude-annotated":
+				target.Filter.ExcludeAnnotated = splitList(value)
+			case "services":
+				target.Services = splitList(value)
+				if len(target.Services) == 0 {
+					return nil, fmt.Errorf("-services=<Interface,...> flag requires at least one interface")
+				}
+				if duplicate, ok := findDuplicate(target.Services); ok {
+					return nil, fmt.Errorf("-services=%v: duplicate interface %v", value, duplicate)
+				}
+			case "constants":
+				target.Constants = true
+			default:
 				target.Opts[name] = value
 			}
 		} else {
@@ -195,5 +358,31 @@ func parseWebrpcCommand(cmd string) (*Target, error) {
 		return nil, fmt.Errorf("-out=<path> flag is required")
 	}
 
+	if err := target.Filter.validate(); err != nil {
+		return nil, err
+	}
+
 	return target, nil
 }
+
+func findDuplicate(list []string) (string, bool) {
+	seen := map[string]struct{}{}
+	for _, item := range list {
+		if _, ok := seen[item]; ok {
+			return item, true
+		}
+		seen[item] = struct{}{}
+	}
+	return "", false
+}
+
+// Splits comma-separated list of values, ie. Get*,List*.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,195 @@
+package gospeak
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces values of sensitive fields in the Redact() output.
+const Redacted = "[REDACTED]"
+
+// Redact returns JSON of the given value with sensitive fields replaced by "[REDACTED]",
+// so request and response payloads can be logged safely, ie.:
+//
+//	type LoginRequest struct {
+//		Username string `json:"username"`
+//		Password string `json:"password" gospeak:"sensitive"`
+//	}
+//
+//	payload, _ := gospeak.Redact(req) // {"password":"[REDACTED]","username":"john"}
+//
+// The fields are redacted in nested structs, lists and maps too. Null values are kept,
+// so the logs still show whether the field was given. Object keys are sorted.
+func Redact(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("redact: %w", err)
+	}
+
+	return json.Marshal(redactValue(reflect.ValueOf(v), tree))
+}
+
+// Redacts sensitive fields of the decoded JSON tree by walking the original Go value.
+func redactValue(v reflect.Value, tree any) any {
+	v, ok := indirectValue(v)
+	if !ok {
+		return tree
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if obj, ok := tree.(map[string]any); ok {
+			redactStruct(v, obj)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if list, ok := tree.([]any); ok && len(list) == v.Len() {
+			for i := range list {
+				list[i] = redactValue(v.Index(i), list[i])
+			}
+		}
+
+	case reflect.Map:
+		if obj, ok := tree.(map[string]any); ok {
+			iter := v.MapRange()
+			for iter.Next() {
+				key, ok := mapKey(iter.Key())
+				if !ok {
+					return Redacted // don't leak values we can't walk
+				}
+				if value, ok := obj[key]; ok {
+					obj[key] = redactValue(iter.Value(), value)
+				}
+			}
+		}
+	}
+
+	return tree
+}
+
+func redactStruct(v reflect.Value, obj map[string]any) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+
+		// Promoted fields of embedded structs.
+		if field.Anonymous && name == "" {
+			if embedded, ok := indirectValue(v.Field(i)); ok && embedded.Kind() == reflect.Struct {
+				redactStruct(embedded, obj)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		value, ok := obj[name]
+		if !ok {
+			continue
+		}
+
+		if isSensitive(field.Tag) {
+			if value != nil {
+				obj[name] = Redacted
+			}
+			continue
+		}
+		obj[name] = redactValue(v.Field(i), value)
+	}
+}
+
+// Reports whether the field is marked by `gospeak:"sensitive"` struct tag.
+func isSensitive(tag reflect.StructTag) bool {
+	for _, option := range strings.Split(tag.Get("gospeak"), ",") {
+		if strings.TrimSpace(option) == "sensitive" {
+			return true
+		}
+	}
+	return false
+}
+
+// Dereferences pointers, interfaces and Optional/Nullable values.
+// Returns false if the value is nil or absent.
+func indirectValue(v reflect.Value) (reflect.Value, bool) {
+	for {
+		switch v.Kind() {
+		case reflect.Invalid:
+			return v, false
+		case reflect.Pointer, reflect.Interface:
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		case reflect.Struct:
+			typ := v.Type()
+			if typ.PkgPath() != "github.com/golang-cz/gospeak" {
+				return v, true
+			}
+			switch {
+			case strings.HasPrefix(typ.Name(), "Optional["):
+				if !v.FieldByName("Set").Bool() {
+					return v, false
+				}
+			case strings.HasPrefix(typ.Name(), "Nullable["):
+				if !v.FieldByName("Valid").Bool() {
+					return v, false
+				}
+			default:
+				return v, true
+			}
+			v = v.FieldByName("Value")
+		default:
+			return v, true
+		}
+	}
+}
+
+// Returns JSON object key of the map key, ie. "1" for int key. Resolves the key
+// the same way as encoding/json: strings, encoding.TextMarshaler, then integers.
+func mapKey(key reflect.Value) (string, bool) {
+	if key.Kind() == reflect.String {
+		return key.String(), true
+	}
+
+	if key.CanInterface() {
+		if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+			if key.Kind() == reflect.Pointer && key.IsNil() {
+				return "", true
+			}
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return "", false
+			}
+			return string(text), true
+		}
+	}
+
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), true
+	}
+	return "", false
+}
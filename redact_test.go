@@ -0,0 +1,90 @@
+package gospeak
+
+import (
+	"net/netip"
+	"testing"
+)
+
+type loginRequest struct {
+	Username string           `json:"username"`
+	Password string           `json:"password" gospeak:"sensitive"`
+	Token    *string          `json:"token,omitempty" gospeak:"sensitive,readonly"`
+	PIN      Optional[int]    `json:"pin,omitzero" gospeak:"sensitive"`
+	Recovery Nullable[string] `json:"recovery" gospeak:"sensitive"`
+	Device   *device          `json:"device"`
+	Keys     []apiKey         `json:"keys"`
+	Secrets  map[string]any   `json:"secrets"`
+	Meta     any              `json:"meta"`
+	audit
+}
+
+type device struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret" gospeak:"sensitive"`
+}
+
+type apiKey struct {
+	ID  int    `json:"id"`
+	Key string `json:"key" gospeak:"sensitive"`
+}
+
+type audit struct {
+	IP      string `json:"ip"`
+	Session string `json:"session" gospeak:"sensitive"`
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	token := "tkn"
+	tt := []struct {
+		in  any
+		out string
+	}{
+		{
+			in: &loginRequest{
+				Username: "john",
+				Password: "secret",
+				Token:    &token,
+				PIN:      OptionalOf(1234),
+				Device:   &device{Name: "phone", Secret: "s3cr3t"},
+				Keys:     []apiKey{{ID: 1, Key: "k1"}, {ID: 2, Key: "k2"}},
+				Secrets:  map[string]any{"a": device{Name: "laptop", Secret: "x"}},
+				Meta:     apiKey{ID: 3, Key: "k3"},
+				audit:    audit{IP: "127.0.0.1", Session: "sess"},
+			},
+			out: `{"device":{"name":"phone","secret":"[REDACTED]"},"ip":"127.0.0.1","keys":[{"id":1,"key":"[REDACTED]"},{"id":2,"key":"[REDACTED]"}],"meta":{"id":3,"key":"[REDACTED]"},"password":"[REDACTED]","pin":"[REDACTED]","recovery":null,"secrets":{"a":{"name":"laptop","secret":"[REDACTED]"}},"session":"[REDACTED]","token":"[REDACTED]","username":"john"}`,
+		},
+		{
+			in:  loginRequest{Username: "jane", Recovery: NullableOf("code")},
+			out: `{"device":null,"ip":"","keys":null,"meta":null,"password":"[REDACTED]","recovery":"[REDACTED]","secrets":null,"session":"[REDACTED]","username":"jane"}`,
+		},
+		{
+			in:  []device{{Name: "a", Secret: "b"}},
+			out: `[{"name":"a","secret":"[REDACTED]"}]`,
+		},
+		{
+			in:  map[int]*apiKey{7: {ID: 7, Key: "k"}},
+			out: `{"7":{"id":7,"key":"[REDACTED]"}}`,
+		},
+		{
+			// Keys encoded by MarshalText().
+			in:  map[netip.Addr]device{netip.MustParseAddr("10.0.0.1"): {Name: "router", Secret: "s"}},
+			out: `{"10.0.0.1":{"name":"router","secret":"[REDACTED]"}}`,
+		},
+		{
+			in:  nil,
+			out: `null`,
+		},
+	}
+
+	for _, tc := range tt {
+		got, err := Redact(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tc.out {
+			t.Errorf("Redact(%#v):\nwant %s\ngot  %s", tc.in, tc.out, got)
+		}
+	}
+}
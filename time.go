@@ -0,0 +1,298 @@
+package gospeak
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is time.Duration encoded as ISO-8601 duration string in JSON, ie. "PT1H30M".
+// Unmarshals both ISO-8601 ("PT1H30M", "P1DT2H") and Go duration ("1h30m") strings.
+//
+// Use Duration instead of time.Duration, which is encoded as int64 nanoseconds by encoding/json.
+type Duration time.Duration
+
+// String returns ISO-8601 duration, ie. "PT1H30M".
+func (d Duration) String() string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+
+	// Use uint64 to handle math.MinInt64.
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+	b.WriteString("PT")
+
+	hours := u / uint64(time.Hour)
+	u -= hours * uint64(time.Hour)
+	minutes := u / uint64(time.Minute)
+	u -= minutes * uint64(time.Minute)
+	seconds := u / uint64(time.Second)
+	nanos := u - seconds*uint64(time.Second)
+
+	if hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+	if seconds > 0 || nanos > 0 {
+		b.WriteString(strconv.FormatUint(seconds, 10))
+		if nanos > 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		b.WriteString("S")
+	}
+
+	return b.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(data []byte) error {
+	duration, err := ParseDuration(string(data))
+	if err != nil {
+		return err
+	}
+	*d = duration
+	return nil
+}
+
+// ParseDuration parses ISO-8601 duration ("PT1H30M", "P1DT2H") or Go duration ("1h30m").
+// ISO-8601 years and months are not supported, since they don't have a fixed length.
+// Days are 24 hours and weeks are 7 days.
+func ParseDuration(s string) (Duration, error) {
+	iso, negative := s, false
+	switch {
+	case strings.HasPrefix(iso, "-"):
+		iso, negative = iso[1:], true
+	case strings.HasPrefix(iso, "+"):
+		iso = iso[1:]
+	}
+
+	if !strings.HasPrefix(iso, "P") {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: expected ISO-8601 or Go duration", s)
+		}
+		return Duration(d), nil
+	}
+
+	invalid := fmt.Errorf("invalid ISO-8601 duration %q", s)
+	outOfRange := fmt.Errorf("invalid ISO-8601 duration %q: out of range", s)
+
+	datePart, timePart, hasTime := strings.Cut(iso[1:], "T")
+	if datePart == "" && timePart == "" {
+		return 0, invalid
+	}
+	if hasTime && timePart == "" {
+		return 0, invalid
+	}
+
+	type unit struct {
+		designator byte
+		length     uint64
+	}
+
+	// The negative range is one nanosecond longer, ie. math.MinInt64.
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++
+	}
+
+	// Sum up in nanoseconds. The integer part of the values is exact, the fraction
+	// is rounded down to nanoseconds, like time.ParseDuration() does.
+	var total uint64
+	add := func(part string, units []unit) error {
+		for part != "" {
+			i := strings.IndexFunc(part, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+			if i <= 0 {
+				return invalid
+			}
+			// Units must be in descending order, each at most once, ie. "PT1H30M" but not "PT30M1H" or "PT1H1H".
+			for len(units) > 0 && units[0].designator != part[i] {
+				units = units[1:]
+			}
+			if len(units) == 0 {
+				return invalid
+			}
+			length := units[0].length
+			units = units[1:]
+
+			intPart, fracPart, _ := strings.Cut(part[:i], ".")
+			if (intPart == "" && fracPart == "") || strings.Contains(fracPart, ".") {
+				return invalid
+			}
+
+			var value uint64
+			if intPart != "" {
+				n, err := strconv.ParseUint(intPart, 10, 64)
+				if err != nil {
+					return outOfRange
+				}
+				hi, lo := bits.Mul64(n, length)
+				if hi != 0 {
+					return outOfRange
+				}
+				value = lo
+			}
+			if fracPart != "" {
+				fracPart = fracPart[:min(len(fracPart), 18)] // more digits don't change the nanoseconds
+				frac, _ := strconv.ParseUint(fracPart, 10, 64)
+				scale := math.Pow10(len(fracPart))
+				value += uint64(float64(frac) * (float64(length) / scale))
+			}
+
+			if value > limit-total {
+				return outOfRange
+			}
+			total += value
+			part = part[i+1:]
+		}
+		return nil
+	}
+
+	if err := add(datePart, []unit{{'W', uint64(7 * 24 * time.Hour)}, {'D', uint64(24 * time.Hour)}}); err != nil {
+		return 0, err
+	}
+	if err := add(timePart, []unit{{'H', uint64(time.Hour)}, {'M', uint64(time.Minute)}, {'S', uint64(time.Second)}}); err != nil {
+		return 0, err
+	}
+
+	if negative {
+		return Duration(-total), nil // -(1<<63) wraps to math.MinInt64
+	}
+	return Duration(total), nil
+}
+
+// Date is a civil date without time and location, encoded as "2006-01-02" in JSON.
+// The zero Date is encoded as empty string.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+const dateLayout = "2006-01-02"
+
+// DateOf returns the date of the given time in its location.
+func DateOf(t time.Time) Date {
+	var d Date
+	d.Year, d.Month, d.Day = t.Date()
+	return d
+}
+
+// ParseDate parses "2006-01-02" date.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD", s)
+	}
+	return DateOf(t), nil
+}
+
+// String returns "2006-01-02" date.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether the date is the zero value.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight of the date in the given location.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	if d.IsZero() {
+		return []byte{}, nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = Date{}
+		return nil
+	}
+	date, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// TimeOfDay is a civil time of day without date and location, encoded as "15:04:05" in JSON.
+// Fractional seconds are encoded only if non-zero, ie. "15:04:05.5".
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// TimeOfDayOf returns the time of day of the given time in its location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{
+		Hour:       t.Hour(),
+		Minute:     t.Minute(),
+		Second:     t.Second(),
+		Nanosecond: t.Nanosecond(),
+	}
+}
+
+// ParseTimeOfDay parses "15:04:05" or "15:04:05.999999999" time of day.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04:05.999999999", s)
+	if err != nil {
+		return TimeOfDay{}, fmt.Errorf("invalid time of day %q: expected HH:MM:SS", s)
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// String returns "15:04:05" or "15:04:05.999999999" time of day.
+func (t TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// On returns the time of day on the given date in the given location.
+func (t TimeOfDay) On(d Date, loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TimeOfDay) UnmarshalText(data []byte) error {
+	timeOfDay, err := ParseTimeOfDay(string(data))
+	if err != nil {
+		return err
+	}
+	*t = timeOfDay
+	return nil
+}
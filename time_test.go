@@ -0,0 +1,168 @@
+package gospeak
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tt := []struct {
+		in  time.Duration
+		out string
+	}{
+		{in: 0, out: "PT0S"},
+		{in: time.Second, out: "PT1S"},
+		{in: 90 * time.Minute, out: "PT1H30M"},
+		{in: 26*time.Hour + 3*time.Second, out: "PT26H3S"},
+		{in: 1500 * time.Millisecond, out: "PT1.5S"},
+		{in: time.Nanosecond, out: "PT0.000000001S"},
+		{in: -90 * time.Second, out: "-PT1M30S"},
+		{in: math.MaxInt64, out: "PT2562047H47M16.854775807S"},
+		{in: math.MinInt64, out: "-PT2562047H47M16.854775808S"},
+	}
+	for _, tc := range tt {
+		b, err := json.Marshal(Duration(tc.in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(b); got != `"`+tc.out+`"` {
+			t.Errorf("%v: expected %q, got %s", tc.in, tc.out, got)
+		}
+
+		var d Duration
+		if err := json.Unmarshal(b, &d); err != nil {
+			t.Fatalf("%v: %v", tc.in, err)
+		}
+		if time.Duration(d) != tc.in {
+			t.Errorf("%s: expected %v, got %v", b, tc.in, time.Duration(d))
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tt := []struct {
+		in  string
+		out time.Duration
+		err bool
+	}{
+		{in: "1h30m", out: 90 * time.Minute},
+		{in: "-1.5s", out: -1500 * time.Millisecond},
+		{in: "PT1H30M", out: 90 * time.Minute},
+		{in: "P1DT2H", out: 26 * time.Hour},
+		{in: "P1W", out: 7 * 24 * time.Hour},
+		{in: "PT0.5S", out: 500 * time.Millisecond},
+		{in: "-PT1M", out: -time.Minute},
+		{in: "P", err: true},
+		{in: "PT", err: true},
+		{in: "P1Y", err: true},
+		{in: "P1M", err: true},
+		{in: "PTH", err: true},
+		{in: "PT1H1H", err: true},
+		{in: "PT30M1H", err: true},
+		{in: "P1D1W", err: true},
+		{in: "PT9999999999999H", err: true},
+		{in: "P9999999999W", err: true},
+		{in: "PT2562047H47M16S", out: 2562047*time.Hour + 47*time.Minute + 16*time.Second},
+		{in: "PT2562047H47M17S", err: true},
+		{in: "PT2562047H47M16.854775807S", out: math.MaxInt64},
+		{in: "PT2562047H47M16.854775808S", err: true},
+		{in: "-PT2562047H47M16.854775808S", out: math.MinInt64},
+		{in: "-PT2562047H47M16.854775809S", err: true},
+		{in: "PT0.000000001S", out: time.Nanosecond},
+		{in: "PT1.2.3S", err: true},
+		{in: "PT.S", err: true},
+		{in: "1 hour", err: true},
+	}
+	for _, tc := range tt {
+		d, err := ParseDuration(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%q: expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.in, err)
+			continue
+		}
+		if time.Duration(d) != tc.out {
+			t.Errorf("%q: expected %v, got %v", tc.in, tc.out, time.Duration(d))
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	b, err := json.Marshal(Date{Year: 2024, Month: time.February, Day: 29})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `"2024-02-29"` {
+		t.Errorf("expected \"2024-02-29\", got %s", b)
+	}
+
+	var d Date
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d != (Date{Year: 2024, Month: time.February, Day: 29}) {
+		t.Errorf("unexpected date %v", d)
+	}
+
+	if err := json.Unmarshal([]byte(`"2023-02-29"`), &d); err == nil {
+		t.Errorf("expected invalid date error")
+	}
+
+	// Zero date round-trips via empty string.
+	b, err = json.Marshal(Date{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `""` {
+		t.Errorf("expected \"\", got %s", b)
+	}
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	if !d.IsZero() {
+		t.Errorf("expected zero date, got %v", d)
+	}
+
+	if got := DateOf(time.Date(2024, 1, 2, 23, 59, 0, 0, time.UTC)); got.String() != "2024-01-02" {
+		t.Errorf("expected 2024-01-02, got %v", got)
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	tt := []struct {
+		in  TimeOfDay
+		out string
+	}{
+		{in: TimeOfDay{}, out: "00:00:00"},
+		{in: TimeOfDay{Hour: 15, Minute: 4, Second: 5}, out: "15:04:05"},
+		{in: TimeOfDay{Hour: 23, Minute: 59, Second: 59, Nanosecond: 500000000}, out: "23:59:59.5"},
+	}
+	for _, tc := range tt {
+		b, err := json.Marshal(tc.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `"`+tc.out+`"` {
+			t.Errorf("expected %q, got %s", tc.out, b)
+		}
+
+		var got TimeOfDay
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.in {
+			t.Errorf("%s: expected %+v, got %+v", b, tc.in, got)
+		}
+	}
+
+	var got TimeOfDay
+	if err := json.Unmarshal([]byte(`"25:00:00"`), &got); err == nil {
+		t.Errorf("expected invalid time of day error")
+	}
+}
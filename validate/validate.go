@@ -0,0 +1,387 @@
+// Package validate validates request structs by `validate:"..."` and `gospeak:"..."`
+// struct tags. The rules are the same, which the schema parser emits as OpenAPI
+// constraints, so the server and the clients agree on them:
+//
+//	type CreateUserRequest struct {
+//		Name  string `json:"name" validate:"required,min=1,max=64"`
+//		Email string `json:"email" validate:"required,email"`
+//		Age   int    `json:"age" gospeak:"min=18"`
+//	}
+//
+//	func (s *Server) CreateUser(ctx context.Context, req *CreateUserRequest) (*User, error) {
+//		if err := validate.Struct(req); err != nil {
+//			return nil, proto.ErrWebrpcBadRequest.WithCause(err)
+//		}
+//		...
+//	}
+//
+// Supported rules are required, omitempty, min, max, len, gt, gte, lt, lte, oneof,
+// pattern, dive, formats (email, url, uri, uuid, ip, ipv4, ipv6, hostname) and
+// patterns (alpha, alphanum, numeric). Other rules are ignored, so they can be
+// validated by 3rd party validators.
+package validate
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/golang-cz/gospeak/internal/rules"
+)
+
+// FieldError is a validation error of a single field.
+type FieldError struct {
+	Field   string `json:"field"` // JSON path of the field, ie. items[0].name
+	Rule    string `json:"rule"`  // failed rule, ie. max=64
+	Message string `json:"msg"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Field, e.Message)
+}
+
+// Errors is a list of field validation errors.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Struct validates the given struct (or pointer to struct) and its nested structs.
+// Returns Errors, if any field is invalid. Wrap it with the ErrWebrpcBadRequest
+// error generated by webrpc, so the client gets the per-field details.
+func Struct(v any) error {
+	var errs Errors
+	validateStruct(reflect.ValueOf(v), "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(v reflect.Value, path string, errs *Errors) {
+	v, ok := indirect(v)
+	if !ok || v.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		jsonTag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if jsonTag == "-" {
+			continue
+		}
+
+		// Promoted fields of embedded structs.
+		if field.Anonymous && name == "" {
+			validateStruct(v.Field(i), path, errs)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		validateValue(v.Field(i), name, fieldRules(field.Tag), errs)
+	}
+}
+
+// Returns rules of `validate:"..."` and `gospeak:"..."` struct tags.
+// The gospeak rules validate the field itself, ie. they precede "dive".
+func fieldRules(tag reflect.StructTag) []rules.Rule {
+	var gospeakRules []rules.Rule
+	required := false
+
+	for _, option := range rules.SplitOptions(tag.Get("gospeak")) {
+		name, param, _ := strings.Cut(option, "=")
+		if name == "required" {
+			// Field required by the schema is validated too.
+			required = true
+			continue
+		}
+		if rule, ok, err := rules.GospeakOption(name, param); ok && err == nil {
+			gospeakRules = append(gospeakRules, rule)
+		}
+	}
+
+	fieldRules := rules.FieldRules(rules.Parse(tag.Get("validate")), gospeakRules)
+	if required {
+		fieldRules = append([]rules.Rule{{Name: "required"}}, fieldRules...)
+	}
+
+	return fieldRules
+}
+
+// Validates the value by the rules and its nested structs, if any.
+func validateValue(v reflect.Value, path string, fieldRules []rules.Rule, errs *Errors) {
+	// Pointers and gospeak.Optional/Nullable values are required to be present,
+	// other values are required to be non-zero.
+	_, isWrapper := wrapperSet(v)
+	nillable := isWrapper || v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface
+
+	v, present := indirect(v)
+
+	for i, rule := range fieldRules {
+		switch rule.Name {
+		case "omitempty":
+			if !present || v.IsZero() {
+				return
+			}
+		case "required":
+			if !present || (!nillable && v.IsZero()) {
+				*errs = append(*errs, FieldError{Field: path, Rule: rule.String(), Message: "is required"})
+				return
+			}
+		case "dive":
+			if present {
+				forEachElem(v, path, func(elem reflect.Value, elemPath string) {
+					validateValue(elem, elemPath, fieldRules[i+1:], errs)
+				})
+			}
+			return
+		default:
+			if !present {
+				continue // absent values are validated by required rule only
+			}
+			if msg := checkRule(v, rule); msg != "" {
+				*errs = append(*errs, FieldError{Field: path, Rule: rule.String(), Message: msg})
+			}
+		}
+	}
+
+	if !present {
+		return
+	}
+
+	// Nested structs.
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		forEachElem(v, path, func(elem reflect.Value, elemPath string) {
+			validateValue(elem, elemPath, nil, errs)
+		})
+	}
+}
+
+func forEachElem(v reflect.Value, path string, fn func(elem reflect.Value, elemPath string)) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fn(v.Index(i), fmt.Sprintf("%v[%v]", path, i))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			fn(iter.Value(), fmt.Sprintf("%v[%v]", path, iter.Key()))
+		}
+	}
+}
+
+// Dereferences pointers, interfaces and gospeak.Optional/Nullable wrappers.
+// Returns false if the value is absent (nil, unset or null).
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for {
+		switch v.Kind() {
+		case reflect.Invalid:
+			return v, false
+		case reflect.Pointer, reflect.Interface:
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		case reflect.Struct:
+			set, ok := wrapperSet(v)
+			if !ok {
+				return v, true
+			}
+			if !set {
+				return v, false
+			}
+			v = v.FieldByName("Value")
+		default:
+			return v, true
+		}
+	}
+}
+
+// Reports whether gospeak.Optional or gospeak.Nullable wrapper value is set.
+func wrapperSet(v reflect.Value) (set bool, ok bool) {
+	if v.Kind() != reflect.Struct {
+		return false, false
+	}
+	typ := v.Type()
+	if typ.PkgPath() != "github.com/golang-cz/gospeak" {
+		return false, false
+	}
+	switch {
+	case strings.HasPrefix(typ.Name(), "Optional["):
+		return v.FieldByName("Set").Bool(), true
+	case strings.HasPrefix(typ.Name(), "Nullable["):
+		return v.FieldByName("Valid").Bool(), true
+	}
+	return false, false
+}
+
+// Returns error message, if the value doesn't pass the rule.
+func checkRule(v reflect.Value, rule rules.Rule) string {
+	switch rule.Name {
+	case "min", "max", "len", "gt", "gte", "lt", "lte":
+		return checkSize(v, rule)
+
+	case "oneof":
+		value := fmt.Sprint(v.Interface())
+		for _, allowed := range strings.Fields(rule.Param) {
+			if value == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%v]", rule.Param)
+
+	case "pattern":
+		return checkPattern(v, rule.Param)
+	}
+
+	if pattern, ok := rules.Patterns[rule.Name]; ok {
+		return checkPattern(v, pattern)
+	}
+
+	if _, ok := rules.Formats[rule.Name]; ok {
+		if v.Kind() != reflect.String {
+			return fmt.Sprintf("must be a string of %v format", rule.Name)
+		}
+		if !validFormat(rule.Name, v.String()) {
+			return fmt.Sprintf("must be a valid %v", rule.Name)
+		}
+	}
+
+	return ""
+}
+
+// Checks length of strings, lists and maps, or value of numbers.
+func checkSize(v reflect.Value, rule rules.Rule) string {
+	limit, err := strconv.ParseFloat(rule.Param, 64)
+	if err != nil {
+		return fmt.Sprintf("invalid rule %v: expected number", rule)
+	}
+
+	var value float64
+	subject := "length"
+	switch v.Kind() {
+	case reflect.String:
+		value = float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Array, reflect.Map:
+		value = float64(v.Len())
+		subject = "number of items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(v.Int())
+		subject = "value"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value = float64(v.Uint())
+		subject = "value"
+	case reflect.Float32, reflect.Float64:
+		value = v.Float()
+		subject = "value"
+	default:
+		return fmt.Sprintf("rule %v is not supported for %v", rule, v.Type())
+	}
+
+	var ok bool
+	var op string
+	switch rule.Name {
+	case "min", "gte":
+		ok, op = value >= limit, "at least"
+	case "max", "lte":
+		ok, op = value <= limit, "at most"
+	case "len":
+		ok, op = value == limit, "exactly"
+	case "gt":
+		ok, op = value > limit, "greater than"
+	case "lt":
+		ok, op = value < limit, "less than"
+	}
+	if ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%v must be %v %v", subject, op, formatNumber(limit))
+}
+
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var patterns sync.Map // map[string]*regexp.Regexp
+
+func checkPattern(v reflect.Value, pattern string) string {
+	if v.Kind() != reflect.String {
+		return fmt.Sprintf("must be a string matching %v", pattern)
+	}
+
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Sprintf("invalid pattern %v: %v", pattern, err)
+		}
+		re, _ = patterns.LoadOrStore(pattern, compiled)
+	}
+
+	if !re.(*regexp.Regexp).MatchString(v.String()) {
+		return fmt.Sprintf("must match %v", pattern)
+	}
+	return ""
+}
+
+var (
+	uuidRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+func validFormat(format string, s string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "url":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "uri":
+		_, err := url.ParseRequestURI(s)
+		return err == nil
+	case "uuid":
+		return uuidRegex.MatchString(s)
+	case "ip", "ipv4", "ipv6":
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return false
+		}
+		return format == "ip" || (format == "ipv4") == addr.Is4()
+	case "hostname":
+		return len(s) <= 253 && hostnameRegex.MatchString(s)
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang-cz/gospeak"
+	"github.com/google/go-cmp/cmp"
+)
+
+// webrpcError mimics the WebRPCError type generated by webrpc.
+type webrpcError struct {
+	Code  int
+	Name  string
+	cause error
+}
+
+func (e webrpcError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Name, e.cause)
+}
+
+func (e webrpcError) Unwrap() error {
+	return e.cause
+}
+
+func (e webrpcError) WithCause(cause error) webrpcError {
+	err := e
+	err.cause = cause
+	return err
+}
+
+var (
+	errWebrpcEndpoint   = webrpcError{Code: 0, Name: "WebrpcEndpoint"}
+	errWebrpcBadRequest = webrpcError{Code: -4, Name: "WebrpcBadRequest"}
+)
+
+// Mimics RespondWithError() of the generated server.
+func respondWithError(err error) webrpcError {
+	rpcErr, ok := err.(webrpcError)
+	if !ok {
+		rpcErr = errWebrpcEndpoint.WithCause(err)
+	}
+	return rpcErr
+}
+
+type Address struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip,omitempty" validate:"omitempty,numeric,len=5"`
+}
+
+type Item struct {
+	Name     string `json:"name" validate:"required,max=8"`
+	Quantity int    `json:"quantity" gospeak:"min=1,max=100"`
+}
+
+type CreateOrderRequest struct {
+	Email    string                    `json:"email" validate:"required,email"`
+	Website  string                    `json:"website,omitempty" validate:"omitempty,url"`
+	Status   string                    `json:"status" validate:"oneof=new paid"`
+	Code     string                    `json:"code" gospeak:"pattern=^[A-Z]{3}$"`
+	Tags     []string                  `json:"tags" validate:"max=2,dive,min=2"`
+	Labels   []string                  `json:"labels" validate:"dive,min=2" gospeak:"max=1"`
+	Items    []*Item                   `json:"items" validate:"min=1"`
+	Address  *Address                  `json:"address"`
+	Note     gospeak.Optional[string]  `json:"note,omitzero" validate:"max=4"`
+	Discount gospeak.Nullable[float64] `json:"discount" validate:"gte=0,lt=1"`
+	Ignored  string                    `json:"-" validate:"required"`
+	internal string                    `validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	t.Parallel()
+
+	valid := CreateOrderRequest{
+		Email:   "john@example.com",
+		Status:  "new",
+		Code:    "ABC",
+		Tags:    []string{"go", "rpc"},
+		Items:   []*Item{{Name: "pen", Quantity: 1}},
+		Address: &Address{City: "Prague", Zip: "11000"},
+	}
+
+	if err := Struct(&valid); err != nil {
+		t.Errorf("expected valid request, got %v", err)
+	}
+	if err := Struct(valid); err != nil {
+		t.Errorf("expected valid request, got %v", err)
+	}
+
+	invalid := CreateOrderRequest{
+		Email:    "john",
+		Website:  "example.com",
+		Status:   "shipped",
+		Code:     "abc",
+		Tags:     []string{"go", "r", "c"},
+		Labels:   []string{"a", "bc"},
+		Items:    []*Item{{Name: "fountain pen", Quantity: 0}},
+		Address:  &Address{Zip: "1100"},
+		Note:     gospeak.OptionalOf("too long"),
+		Discount: gospeak.NullableOf(1.0),
+	}
+
+	err := Struct(&invalid)
+	if _, ok := err.(Errors); !ok {
+		t.Fatalf("expected validate.Errors, got %T: %v", err, err)
+	}
+
+	rpcErr := respondWithError(errWebrpcBadRequest.WithCause(err))
+	if rpcErr.Code != errWebrpcBadRequest.Code {
+		t.Fatalf("expected WebrpcBadRequest, got %v", rpcErr)
+	}
+
+	var got Errors
+	if !errors.As(rpcErr, &got) {
+		t.Fatalf("expected validate.Errors cause, got %v", rpcErr)
+	}
+
+	want := Errors{
+		{Field: "email", Rule: "email", Message: "must be a valid email"},
+		{Field: "website", Rule: "url", Message: "must be a valid url"},
+		{Field: "status", Rule: "oneof=new paid", Message: "must be one of [new paid]"},
+		{Field: "code", Rule: "pattern=^[A-Z]{3}$", Message: "must match ^[A-Z]{3}$"},
+		{Field: "tags", Rule: "max=2", Message: "number of items must be at most 2"},
+		{Field: "tags[1]", Rule: "min=2", Message: "length must be at least 2"},
+		{Field: "tags[2]", Rule: "min=2", Message: "length must be at least 2"},
+		{Field: "labels", Rule: "max=1", Message: "number of items must be at most 1"},
+		{Field: "labels[0]", Rule: "min=2", Message: "length must be at least 2"},
+		{Field: "items[0].name", Rule: "max=8", Message: "length must be at most 8"},
+		{Field: "items[0].quantity", Rule: "min=1", Message: "value must be at least 1"},
+		{Field: "address.city", Rule: "required", Message: "is required"},
+		{Field: "address.zip", Rule: "len=5", Message: "length must be exactly 5"},
+		{Field: "note", Rule: "max=4", Message: "length must be at most 4"},
+		{Field: "discount", Rule: "lt=1", Message: "value must be less than 1"},
+	}
+
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected errors:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestStructRequired(t *testing.T) {
+	t.Parallel()
+
+	type Request struct {
+		ID      string                   `json:"id" validate:"required,uuid"`
+		Count   *int                     `json:"count" validate:"required"`
+		Limit   *int                     `json:"limit" validate:"max=10"`
+		Cursor  gospeak.Optional[int]    `json:"cursor,omitzero" gospeak:"required"`
+		Filters map[string]string        `json:"filters" validate:"required,dive,alpha"`
+		IP      gospeak.Nullable[string] `json:"ip" validate:"omitempty,ipv4"`
+	}
+
+	var got Errors
+	if !errors.As(Struct(&Request{}), &got) {
+		t.Fatal("expected validate.Errors")
+	}
+
+	want := Errors{
+		{Field: "id", Rule: "required", Message: "is required"},
+		{Field: "count", Rule: "required", Message: "is required"},
+		{Field: "cursor", Rule: "required", Message: "is required"},
+		{Field: "filters", Rule: "required", Message: "is required"},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected errors:\n%s", cmp.Diff(want, got))
+	}
+
+	zero, limit := 0, 20
+	err := Struct(&Request{
+		ID:      "0d5f8b2e-6c4a-4b7e-9f1a-3c2d1e0f9a8b",
+		Count:   &zero, // non-nil pointer to zero value is present
+		Limit:   &limit,
+		Cursor:  gospeak.OptionalOf(0),
+		Filters: map[string]string{"name": "john1"},
+		IP:      gospeak.NullableOf("::1"),
+	})
+	if !errors.As(err, &got) {
+		t.Fatal("expected validate.Errors")
+	}
+
+	want = Errors{
+		{Field: "limit", Rule: "max=10", Message: "value must be at most 10"},
+		{Field: "filters[name]", Rule: "alpha", Message: "must match ^[a-zA-Z]+$"},
+		{Field: "ip", Rule: "ipv4", Message: "must be a valid ipv4"},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected errors:\n%s", cmp.Diff(want, got))
+	}
+}